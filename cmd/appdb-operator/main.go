@@ -1,115 +1,239 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
-	"net/http/httputil"
-	"os"
 
+	"github.com/danisla/appdb-operator/pkg/appdb"
+	"github.com/danisla/appdb-operator/pkg/appdbinstance"
+	"github.com/danisla/appdb-operator/pkg/conversion"
+	"github.com/danisla/appdb-operator/pkg/events"
+	"github.com/danisla/appdb-operator/pkg/hook"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/metrics"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	"github.com/danisla/appdb-operator/pkg/server"
+	"github.com/danisla/appdb-operator/pkg/standalone"
 	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
-	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	vaultv1 "github.com/danisla/appdb-operator/pkg/vault"
+	webhookv1 "github.com/danisla/appdb-operator/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 var (
-	config         Config
-	tfDriverConfig tfdriverv1.TerraformDriverConfig
+	config        operator.Config
+	vaultClient   *vaultv1.Client
+	eventRecorder *events.Recorder
 )
 
 func init() {
-	config = Config{
-		Project:    "", // Derived from instance metadata server
-		ProjectNum: "", // Derived from instance metadata server
+	config = operator.Config{
+		Project:                      "",                                      // Override with env var: PROJECT, derived from instance metadata server when available
+		ProjectNum:                   "",                                      // Override with env var: PROJECT_NUM, derived from instance metadata server when available
+		UseMetadataServer:            true,                                    // Override with env var: USE_METADATA_SERVER
+		CloudSQLProxyImage:           "gcr.io/cloudsql-docker/gce-proxy:1.16", // Override with env var: CLOUD_SQL_PROXY_IMAGE
+		CloudSQLProxyImagePullPolicy: corev1.PullIfNotPresent,                 // Override with env var: CLOUD_SQL_PROXY_IMAGE_PULL_POLICY
+		ProxyInjectorPort:            "8443",                                  // Override with env var: PROXY_INJECTOR_PORT
+		ConversionWebhookPort:        "9443",                                  // Override with env var: CONVERSION_WEBHOOK_PORT
+		ListenAddr:                   ":8080",                                 // Override with env var: LISTEN_ADDR
+		EnableAppDB:                  true,                                    // Override with env var: ENABLE_APPDB_CONTROLLER
+		EnableAppDBInstance:          true,                                    // Override with env var: ENABLE_APPDBINSTANCE_CONTROLLER
+		TFDriver: tfdriverv1.TerraformDriverConfig{
+			ImagePullPolicy:            corev1.PullIfNotPresent,               // Override with env var: TF_IMAGE_PULL_POLICY
+			BackendPrefix:              tfdriverv1.DEFAULT_TF_BACKEND_PREFIX,  // Override with env var: TF_BACKEND_PREFIX
+			MaxAttempts:                tfdriverv1.DEFAULT_TF_MAX_ATTEMPTS,    // Override with env var: TF_MAX_ATTEMPTS
+			GoogleProviderConfigSecret: tfdriverv1.DEFAULT_TF_PROVIDER_SECRET, // Override with env var: TF_GOOGLE_PROVIDER_SECRET
+		},
+		Vault: vaultv1.VaultConfig{
+			DatabaseMount: vaultv1.DEFAULT_VAULT_DATABASE_MOUNT, // Override with env var: VAULT_DATABASE_MOUNT
+		},
+		Webhook: webhookv1.Config{
+			AuthMode: webhookv1.AuthModeNone, // Override with env var: WEBHOOK_AUTH_MODE
+		},
+		Standalone: standalone.Config{
+			Mode:                    standalone.ModeWebhook,                       // Override with env var: CONTROLLER_MODE
+			LeaderElection:          true,                                         // Override with env var: LEADER_ELECT
+			LeaderElectionID:        "appdb-operator",                             // Override with env var: LEADER_ELECTION_ID
+			LeaderElectionNamespace: standalone.DEFAULT_LEADER_ELECTION_NAMESPACE, // Override with env var: LEADER_ELECTION_NAMESPACE
+			MaxConcurrentReconciles: standalone.DEFAULT_MAX_CONCURRENT_RECONCILES, // Override with env var: MAX_CONCURRENT_RECONCILES
+			ResyncPeriod:            standalone.DEFAULT_RESYNC_PERIOD,             // Override with env var: RESYNC_PERIOD
+		},
+		Logging: logging.Config{
+			Level:  "info", // Override with env var: LOG_LEVEL
+			Format: "json", // Override with env var: LOG_FORMAT
+		},
 	}
 
-	if err := config.loadAndValidate(); err != nil {
+	// The flags override the environment and the config file.
+	config.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	if err := config.LoadAndValidate(); err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
-	tfDriverConfig = tfdriverv1.TerraformDriverConfig{}
+	eventRecorder = events.NewRecorder(config.Clientset, "appdb-operator")
 
-	if err := tfDriverConfig.LoadAndValidate(config.Project); err != nil {
-		log.Fatalf("Failed to load terraform driver config: %v", err)
+	if config.Vault.Enabled() == true {
+		var err error
+		vaultClient, err = vaultv1.NewClient(&config.Vault)
+		if err != nil {
+			log.Fatalf("Failed to create vault client: %v", err)
+		}
+	}
+
+	if config.EnableAppDB == true {
+		appdb.Setup(&config, &config.TFDriver, vaultClient, eventRecorder)
+	}
+	if config.EnableAppDBInstance == true {
+		appdbinstance.Setup(&config, &config.TFDriver, eventRecorder)
 	}
 }
 
 func main() {
-	http.HandleFunc("/healthz", healthzHandler())
-	http.HandleFunc("/", webhookHandler())
+	shutdownTracing, err := tracing.Init("appdb-operator")
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
 
-	log.Printf("[INFO] Initialized controller on port 80\n")
-	log.Fatal(http.ListenAndServe(":8081", nil))
-}
+	var mgr manager.Manager
+	if config.Standalone.Mode == standalone.ModeManager {
+		mgr, err = standalone.NewManager(config.RestConfig, &config.Standalone)
+		if err != nil {
+			log.Fatalf("Failed to create controller manager: %v", err)
+		}
+		// Registers the informers for the children with the kube client, before it is started.
+		for _, c := range standaloneControllers() {
+			if err := standalone.Add(mgr, config.KubeClient, &config.Standalone, c); err != nil {
+				log.Fatalf("Failed to create controller %s: %v", c.Name, err)
+			}
+		}
+	}
 
-func healthzHandler() func(w http.ResponseWriter, r *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, "OK\n")
+	stopCh := make(chan struct{})
+	if err := config.KubeClient.Start(stopCh); err != nil {
+		log.Fatalf("Failed to start informers: %v", err)
 	}
-}
 
-func webhookHandler() func(w http.ResponseWriter, r *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var err error
-		var req SyncRequest
-		var desiredStatus *appdbv1.AppDBOperatorStatus
-		var desiredChildren *[]interface{}
-		var parentType ParentType
-
-		if r.Method != "POST" {
-			w.WriteHeader(http.StatusBadRequest)
-			fmt.Fprint(w, "Unsupported method\n")
-			return
-		}
+	if config.EnableAppDB == true {
+		metrics.RegisterAppDBCollector(config.KubeClient.ListAppDBs, appdb.ConditionStatusOrder)
+	}
+	if config.EnableAppDBInstance == true {
+		metrics.RegisterAppDBInstanceCollector(config.KubeClient.ListAppDBInstances)
+	}
 
-		if os.Getenv("HTTP_DEBUG") != "" {
-			log.Printf("---HTTP REQUEST %s %s ---", r.Method, r.URL.String())
-			reqDump, _ := httputil.DumpRequest(r, true)
-			log.Println(string(reqDump))
-		}
+	authenticator, err := webhookv1.NewAuthenticator(&config.Webhook, config.Clientset)
+	if err != nil {
+		log.Fatalf("Failed to create webhook authenticator: %v", err)
+	}
 
-		reqBody, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			log.Printf("[ERROR] Failed to read request body: %v", err)
-			return
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", server.HealthzHandler())
+	mux.HandleFunc("/readyz", server.ReadyzHandler(readyChecks()...))
+	mux.Handle("/metrics", metrics.Handler())
+	if config.Standalone.Mode == standalone.ModeWebhook {
+		if config.EnableAppDB == true {
+			mux.Handle("/sync/appdb", webhookv1.RequireAuth(authenticator, hook.Handler("AppDB", appdb.Sync)))
+			mux.Handle("/finalize/appdb", webhookv1.RequireAuth(authenticator, hook.Handler("AppDB", appdb.Finalize)))
+		}
+		if config.EnableAppDBInstance == true {
+			mux.Handle("/sync/appdbinstance", webhookv1.RequireAuth(authenticator, hook.Handler("AppDBInstance", appdbinstance.Sync)))
 		}
+	}
 
-		err = json.Unmarshal(reqBody, &req)
+	syncServer := server.New(config.ListenAddr, mux)
+	if config.Webhook.TLSEnabled() {
+		tlsConfig, err := config.Webhook.TLSConfig()
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			log.Printf("[ERROR] Could not parse SyncRequest: %v", err)
-			return
+			log.Fatalf("Failed to load webhook TLS config: %v", err)
 		}
+		syncServer = server.NewTLS(config.ListenAddr, mux, config.Webhook.TLSCertFile, config.Webhook.TLSKeyFile)
+		syncServer.TLSConfig = tlsConfig
+	}
 
-		switch req.Parent.Kind {
-		case "AppDB":
-			parentType = ParentDB
-		}
-		desiredStatus, desiredChildren, err = sync(parentType, &req.Parent, &req.Children)
+	servers := []server.Server{syncServer}
 
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			log.Printf("[ERROR] Could not sync state: %v", err)
-		}
+	if config.EnableAppDB == true && config.ProxyInjectorTLSCertFile != "" && config.ProxyInjectorTLSKeyFile != "" {
+		injectorMux := http.NewServeMux()
+		injectorMux.HandleFunc("/mutate", appdb.ProxyInjectorHandler())
 
-		resp := SyncResponse{
-			Status:   *desiredStatus,
-			Children: *desiredChildren,
-		}
+		servers = append(servers, server.NewTLS(":"+config.ProxyInjectorPort, injectorMux, config.ProxyInjectorTLSCertFile, config.ProxyInjectorTLSKeyFile))
+		log.Printf("[INFO] Initialized Cloud SQL proxy sidecar injector on port %s", config.ProxyInjectorPort)
+	}
 
-		data, err := json.Marshal(resp)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			log.Printf("[ERROR] Could not generate SyncResponse: %v", err)
-			return
-		}
-		w.Write(data)
+	// The conversion webhook is independent of the enabled controllers, the API server calls it for any v2 request.
+	if config.ConversionWebhookTLSCertFile != "" && config.ConversionWebhookTLSKeyFile != "" {
+		conversionMux := http.NewServeMux()
+		conversionMux.HandleFunc("/convert", conversion.Handler())
 
-		if os.Getenv("HTTP_DEBUG") != "" {
-			log.Printf("---JSON RESPONSE %s %s ---", r.Method, r.URL.String())
-			log.Println(string(data))
-		}
+		servers = append(servers, server.NewTLS(":"+config.ConversionWebhookPort, conversionMux, config.ConversionWebhookTLSCertFile, config.ConversionWebhookTLSKeyFile))
+		log.Printf("[INFO] Initialized CRD conversion webhook on port %s", config.ConversionWebhookPort)
 	}
+
+	log.Printf("[INFO] Initialized controllers on %s, AppDB: %t, AppDBInstance: %t, TLS: %t, auth mode: %s", config.ListenAddr, config.EnableAppDB, config.EnableAppDBInstance, config.Webhook.TLSEnabled(), config.Webhook.AuthMode)
+
+	if mgr != nil {
+		go func() {
+			// Blocks until stopCh is closed, reconciles start when the leader election is won.
+			if err := mgr.Start(stopCh); err != nil {
+				log.Fatalf("Failed to run controller manager: %v", err)
+			}
+		}()
+		log.Printf("[INFO] Started controller manager, leader election ID: %s", config.Standalone.LeaderElectionID)
+	}
+
+	runErr := server.Run(server.DEFAULT_SHUTDOWN_TIMEOUT, servers...)
+
+	close(stopCh)
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Printf("[WARN] Failed to flush traces: %v", err)
+	}
+
+	if runErr != nil {
+		log.Fatalf("%v", runErr)
+	}
+}
+
+// standaloneControllers returns the enabled controllers for the standalone manager mode, they share the manager and its leader election.
+func standaloneControllers() []standalone.Controller {
+	controllers := make([]standalone.Controller, 0)
+	if config.EnableAppDB == true {
+		controllers = append(controllers, appdb.MakeStandaloneController())
+	}
+	if config.EnableAppDBInstance == true {
+		controllers = append(controllers, appdbinstance.MakeStandaloneController())
+	}
+	return controllers
+}
+
+// readyChecks returns the checks for /readyz: the API server is reachable, the informer caches are synced and the checks of the enabled controllers.
+func readyChecks() []server.Check {
+	checks := []server.Check{
+		server.Check{
+			Name: "kubernetes",
+			Func: func() error {
+				if _, err := config.Clientset.Discovery().ServerVersion(); err != nil {
+					return fmt.Errorf("API server not reachable: %v", err)
+				}
+				if config.KubeClient.HasSynced() == false {
+					return fmt.Errorf("Informer caches not synced")
+				}
+				return nil
+			},
+		},
+	}
+
+	if config.EnableAppDB == true {
+		checks = append(checks, appdb.ReadyChecks()...)
+	}
+	if config.EnableAppDBInstance == true {
+		checks = append(checks, appdbinstance.ReadyChecks()...)
+	}
+
+	return checks
 }
@@ -0,0 +1,118 @@
+// Terraform 0.12 config of the Cloud SQL database for AppDBs with spec.iamUsers, used instead of ../db/main.tf.
+// The resources have the same addresses as ../db/main.tf so the state carries over when the IAM users are added.
+// The CLOUD_IAM_SERVICE_ACCOUNT user type requires google provider 3.66.0, which requires Terraform 0.12.
+terraform {
+  required_version = ">= 0.12"
+
+  required_providers {
+    google = ">= 3.66.0"
+  }
+}
+
+variable "instance" {}
+
+variable "dbname" {}
+
+variable "charset" {
+  default = ""
+}
+
+variable "collation" {
+  default = ""
+}
+
+variable "users" {
+  // CSV list of users, will generate random password for each.
+  default = ""
+}
+
+variable "iam_users" {
+  // CSV list of service account emails, will create a Cloud SQL IAM database user for each.
+  default = ""
+}
+
+variable "database_version" {
+  // Database version of the instance, required to derive the IAM database user names.
+  default = ""
+}
+
+variable "client_cert_name" {
+  // Optional common name of a client certificate to create for TLS connections.
+  default = ""
+}
+
+variable "user_host" {
+  default = "%"
+}
+
+locals {
+  users     = compact(split(",", var.users))
+  iam_users = compact(split(",", var.iam_users))
+  is_mysql  = substr(var.database_version, 0, 5) == "MYSQL"
+}
+
+resource "google_sql_database" "default" {
+  name      = var.dbname
+  instance  = var.instance
+  charset   = var.charset
+  collation = var.collation
+}
+
+resource "random_id" "user-passwords" {
+  count       = length(local.users)
+  byte_length = 8
+}
+
+resource "google_sql_user" "users" {
+  count    = length(local.users)
+  name     = local.users[count.index]
+  instance = var.instance
+  host     = var.user_host
+  password = random_id.user-passwords[count.index].hex
+}
+
+resource "google_sql_ssl_cert" "client-cert" {
+  count       = var.client_cert_name == "" ? 0 : 1
+  common_name = var.client_cert_name
+  instance    = var.instance
+}
+
+data "google_project" "project" {}
+
+// MySQL IAM user names are the service account email without the domain.
+// PostgreSQL IAM user names are the service account email without the .gserviceaccount.com suffix.
+resource "google_sql_user" "iam-users" {
+  count    = length(local.iam_users)
+  name     = local.is_mysql ? replace(local.iam_users[count.index], "/@.*$/", "") : replace(local.iam_users[count.index], ".gserviceaccount.com", "")
+  instance = var.instance
+  type     = "CLOUD_IAM_SERVICE_ACCOUNT"
+}
+
+resource "google_project_iam_member" "iam-users" {
+  count   = length(local.iam_users)
+  project = data.google_project.project.project_id
+  role    = "roles/cloudsql.instanceUser"
+  member  = "serviceAccount:${local.iam_users[count.index]}"
+}
+
+output "iam_user_names" {
+  value = join(",", google_sql_user.iam-users.*.name)
+}
+
+output "user_passwords" {
+  value     = join(",", random_id.user-passwords.*.hex)
+  sensitive = true
+}
+
+output "client_cert" {
+  value = join("", google_sql_ssl_cert.client-cert.*.cert)
+}
+
+output "client_key" {
+  value     = join("", google_sql_ssl_cert.client-cert.*.private_key)
+  sensitive = true
+}
+
+output "server_ca_cert" {
+  value = join("", google_sql_ssl_cert.client-cert.*.server_ca_cert)
+}
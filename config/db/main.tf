@@ -12,6 +12,17 @@ variable "collation" {
 
 variable "users" {
   // CSV list of users, will generate random password for each.    
+  default = ""
+}
+
+variable "client_cert_name" {
+  // Optional common name of a client certificate to create for TLS connections.
+  default = ""
+}
+
+variable "vault_admin_user" {
+  // Optional user for the Vault database secrets engine, created with its own random password.
+  default = ""
 }
 
 variable "user_host" {
@@ -25,12 +36,13 @@ resource "google_sql_database" "default" {
   collation = "${var.collation}"
 }
 
+// Compatible with Terraform 0.11 and 0.12, AppDBs with IAM users use the Terraform 0.12 config in ../db-iam instead.
 locals {
-  users = ["${split(",", "${var.users}")}"]
+  users = "${compact(split(",", var.users))}"
 }
 
 resource "random_id" "user-passwords" {
-  count       = "${length(local.users)}"
+  count       = "${var.users == "" ? 0 : length(local.users)}"
   byte_length = 8
 
   // TODO: Generate new password if instance changes.
@@ -40,14 +52,52 @@ resource "random_id" "user-passwords" {
 }
 
 resource "google_sql_user" "users" {
-  count    = "${length(local.users)}"
+  count    = "${var.users == "" ? 0 : length(local.users)}"
   name     = "${element(local.users, count.index)}"
   instance = "${var.instance}"
   host     = "${var.user_host}"
   password = "${element(random_id.user-passwords.*.hex, count.index)}"
 }
 
+resource "random_id" "vault-admin-password" {
+  count       = "${var.vault_admin_user == "" ? 0 : 1}"
+  byte_length = 16
+}
+
+// Users created with the Cloud SQL API can create users and grant privileges on the databases of the instance.
+resource "google_sql_user" "vault-admin" {
+  count    = "${var.vault_admin_user == "" ? 0 : 1}"
+  name     = "${var.vault_admin_user}"
+  instance = "${var.instance}"
+  host     = "${var.user_host}"
+  password = "${join("", random_id.vault-admin-password.*.hex)}"
+}
+
+resource "google_sql_ssl_cert" "client-cert" {
+  count       = "${var.client_cert_name == "" ? 0 : 1}"
+  common_name = "${var.client_cert_name}"
+  instance    = "${var.instance}"
+}
+
 output "user_passwords" {
   value     = "${join(",", random_id.user-passwords.*.hex)}"
   sensitive = true
 }
+
+output "vault_admin_password" {
+  value     = "${join("", random_id.vault-admin-password.*.hex)}"
+  sensitive = true
+}
+
+output "client_cert" {
+  value = "${join("", google_sql_ssl_cert.client-cert.*.cert)}"
+}
+
+output "client_key" {
+  value     = "${join("", google_sql_ssl_cert.client-cert.*.private_key)}"
+  sensitive = true
+}
+
+output "server_ca_cert" {
+  value = "${join("", google_sql_ssl_cert.client-cert.*.server_ca_cert)}"
+}
@@ -16,6 +16,31 @@ variable "disk_type" {
   default = "PD_SSD"
 }
 
+variable "iam_authentication" {
+  description = "Enable Cloud SQL IAM database authentication, required for AppDB iamUsers."
+  default     = "false"
+}
+
+variable "proxy_auth" {
+  description = "Cloud SQL proxy auth method, one of: serviceAccountKey, workloadIdentity"
+  default     = "serviceAccountKey"
+}
+
+variable "k8s_service_accounts" {
+  description = "CSV list of Kubernetes service accounts as namespace/name bound to the proxy Google service account, required for proxy_auth workloadIdentity."
+  default     = ""
+}
+
+variable "private_network" {
+  description = "Optional VPC network self link. If provided, the instance is created with a private IP and no public IP."
+  default     = ""
+}
+
+variable "require_ssl" {
+  description = "Require SSL connections to the instance."
+  default     = "false"
+}
+
 variable "snapshot_bucket" {
   description = "Optional bucket for snapshots. If not provided, the conventional name will be used in the form of: PROJECT_ID-appdb-operator"
   default     = ""
@@ -28,14 +53,35 @@ resource "random_id" "name" {
 }
 
 locals {
-  project           = "${var.project == "" ? data.google_project.project.project_id : var.project }"
-  name              = "${var.name}-${random_id.name.hex}"
-  port              = "${substr(var.database_version, 0, 5) == "MYSQL" ? "3306" : "5432"}"
-  instance_sa_email = "${module.instance_sa_email.sa_email}"
-  proxy_sa_id       = "cloudsql-proxy-${random_id.name.hex}"
-  proxy_sa_key      = "${google_service_account_key.cloudsql-proxy.private_key}"
-  proxy_sa_email    = "${google_service_account.cloudsql-proxy.email}"
-  snapshot_bucket   = "${var.snapshot_bucket == "" ? format("%s-appdb-operator", data.google_project.project.project_id) : var.snapshot_bucket}"
+  project              = "${var.project == "" ? data.google_project.project.project_id : var.project }"
+  name                 = "${var.name}-${random_id.name.hex}"
+  port                 = "${substr(var.database_version, 0, 5) == "MYSQL" ? "3306" : "5432"}"
+  instance_sa_email    = "${module.instance_sa_email.sa_email}"
+  proxy_sa_id          = "cloudsql-proxy-${random_id.name.hex}"
+  proxy_sa_key         = "${join("", google_service_account_key.cloudsql-proxy.*.private_key)}"
+  proxy_sa_email       = "${google_service_account.cloudsql-proxy.email}"
+  k8s_service_accounts = ["${compact(split(",", var.k8s_service_accounts))}"]
+  iam_flag_name        = "${substr(var.database_version, 0, 5) == "MYSQL" ? "cloudsql_iam_authentication" : "cloudsql.iam_authentication"}"
+  database_flags       = ["${slice(list(map("name", local.iam_flag_name, "value", "on")), 0, var.iam_authentication == "true" ? 1 : 0)}"]
+  snapshot_bucket      = "${var.snapshot_bucket == "" ? format("%s-appdb-operator", data.google_project.project.project_id) : var.snapshot_bucket}"
+}
+
+// Private services access for the VPC network, only created when private_network is provided.
+resource "google_compute_global_address" "private-ip" {
+  count         = "${var.private_network == "" ? 0 : 1}"
+  project       = "${local.project}"
+  name          = "${local.name}-private-ip"
+  purpose       = "VPC_PEERING"
+  address_type  = "INTERNAL"
+  prefix_length = 16
+  network       = "${var.private_network}"
+}
+
+resource "google_service_networking_connection" "private-ip" {
+  count                   = "${var.private_network == "" ? 0 : 1}"
+  network                 = "${var.private_network}"
+  service                 = "servicenetworking.googleapis.com"
+  reserved_peering_ranges = ["${google_compute_global_address.private-ip.*.name}"]
 }
 
 module "db-instance" {
@@ -48,6 +94,20 @@ module "db-instance" {
   user_name        = "admin"
   disk_size        = "${var.disk_size_gb}"
   disk_type        = "${var.disk_type}"
+  database_flags   = ["${local.database_flags}"]
+
+  // The network is read from the service networking connection so that the instance is created after the peering.
+  ip_configuration = [{
+    ipv4_enabled    = "${var.private_network == "" ? "true" : "false"}"
+    private_network = "${var.private_network == "" ? "" : element(concat(google_service_networking_connection.private-ip.*.network, list("")), 0)}"
+    require_ssl     = "${var.require_ssl}"
+  }]
+}
+
+data "google_sql_database_instance" "db-instance" {
+  name       = "${module.db-instance.instance_name}"
+  project    = "${local.project}"
+  depends_on = ["google_service_networking_connection.private-ip"]
 }
 
 resource "google_service_account" "cloudsql-proxy" {
@@ -56,11 +116,20 @@ resource "google_service_account" "cloudsql-proxy" {
   display_name = "${local.name} Cloud SQL Proxy"
 }
 
+// No key is created when the proxy uses Workload Identity.
 resource "google_service_account_key" "cloudsql-proxy" {
+  count              = "${var.proxy_auth == "workloadIdentity" ? 0 : 1}"
   service_account_id = "${google_service_account.cloudsql-proxy.name}"
   public_key_type    = "TYPE_X509_PEM_FILE"
 }
 
+resource "google_service_account_iam_member" "cloudsql-proxy-workload-identity" {
+  count              = "${var.proxy_auth == "workloadIdentity" ? length(local.k8s_service_accounts) : 0}"
+  service_account_id = "${google_service_account.cloudsql-proxy.name}"
+  role               = "roles/iam.workloadIdentityUser"
+  member             = "serviceAccount:${local.project}.svc.id.goog[${element(local.k8s_service_accounts, count.index)}]"
+}
+
 resource "google_project_iam_member" "editor" {
   project = "${var.project}"
   role    = "roles/cloudsql.client"
@@ -102,3 +171,11 @@ output "proxy_sa_key" {
   value     = "${local.proxy_sa_key}"
   sensitive = true
 }
+
+output "private_ip_address" {
+  value = "${data.google_sql_database_instance.db-instance.private_ip_address}"
+}
+
+output "server_ca_cert" {
+  value = "${lookup(data.google_sql_database_instance.db-instance.server_ca_cert[0], "cert")}"
+}
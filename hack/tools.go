@@ -0,0 +1,12 @@
+//go:build tools
+// +build tools
+
+// Package tools pins the versions of the code generators run by hack/update-codegen.sh in go.mod.
+package tools
+
+import (
+	_ "k8s.io/code-generator/cmd/client-gen"
+	_ "k8s.io/code-generator/cmd/deepcopy-gen"
+	_ "k8s.io/code-generator/cmd/informer-gen"
+	_ "k8s.io/code-generator/cmd/lister-gen"
+)
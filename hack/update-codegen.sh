@@ -0,0 +1,56 @@
+#!/usr/bin/env bash
+
+# Regenerates the deepcopy functions, clientset, listers and informers for the ctl.isla.solutions/v1 types.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+SCRIPT_ROOT=$(dirname ${BASH_SOURCE})/..
+
+# The types live in pkg/types instead of pkg/apis/<group>/<version>, so the
+# generators are run individually rather than through generate-groups.sh.
+PKG=github.com/danisla/appdb-operator
+INPUT=${PKG}/pkg/types
+OUTPUT=${PKG}/pkg/client
+HEADER=${SCRIPT_ROOT}/hack/boilerplate.go.txt
+
+# The generators write to <output-base>/<package>, so they write to a
+# temporary directory that is copied over the module afterwards.
+OUTPUT_BASE=$(mktemp -d)
+trap "rm -rf ${OUTPUT_BASE}" EXIT
+
+# The generator versions are pinned in go.mod by hack/tools.go.
+BIN=${OUTPUT_BASE}/bin
+(cd ${SCRIPT_ROOT} && GOBIN=${BIN} go install k8s.io/code-generator/cmd/{deepcopy-gen,client-gen,lister-gen,informer-gen})
+
+${BIN}/deepcopy-gen \
+  --input-dirs ${INPUT},${INPUT}/v2 \
+  -O zz_generated.deepcopy \
+  --bounding-dirs ${INPUT} \
+  --go-header-file ${HEADER} \
+  --output-base ${OUTPUT_BASE}
+
+${BIN}/client-gen \
+  --clientset-name versioned \
+  --input-base "" \
+  --input ${INPUT} \
+  --output-package ${OUTPUT}/clientset \
+  --go-header-file ${HEADER} \
+  --output-base ${OUTPUT_BASE}
+
+${BIN}/lister-gen \
+  --input-dirs ${INPUT} \
+  --output-package ${OUTPUT}/listers \
+  --go-header-file ${HEADER} \
+  --output-base ${OUTPUT_BASE}
+
+${BIN}/informer-gen \
+  --input-dirs ${INPUT} \
+  --versioned-clientset-package ${OUTPUT}/clientset/versioned \
+  --listers-package ${OUTPUT}/listers \
+  --output-package ${OUTPUT}/informers \
+  --go-header-file ${HEADER} \
+  --output-base ${OUTPUT_BASE}
+
+cp -R ${OUTPUT_BASE}/${PKG}/. ${SCRIPT_ROOT}/
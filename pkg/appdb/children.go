@@ -1,4 +1,4 @@
-package main
+package appdb
 
 import (
 	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
@@ -1,19 +1,21 @@
-package main
+package appdb
 
 import (
 	"fmt"
-	"io/ioutil"
 	"strings"
 
+	"github.com/danisla/appdb-operator/pkg/operator"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
 	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
-	tfv1 "github.com/danisla/terraform-operator/pkg/types"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	DEFAULT_CLOUD_SQL_DB_SOURCE_PATH = "/config/db/main.tf"
+	DEFAULT_CLOUD_SQL_DB_SOURCE_PATH     = "/config/db/main.tf"
+	DEFAULT_CLOUD_SQL_DB_IAM_SOURCE_PATH = "/config/db-iam/main.tf"
 )
 
 func makeTFApplyName(parent *appdbv1.AppDB, appdbi appdbv1.AppDBInstance) string {
@@ -23,17 +25,35 @@ func makeTFApplyName(parent *appdbv1.AppDB, appdbi appdbv1.AppDBInstance) string
 func makeCloudSQLDBTerraform(tfApplyName string, parent *appdbv1.AppDB, appdbi appdbv1.AppDBInstance) (appdbv1.Terraform, error) {
 	var tfapply appdbv1.Terraform
 
-	manifest, err := getCloudSQLTerraformManifest(DEFAULT_CLOUD_SQL_DB_SOURCE_PATH)
+	srcPath := DEFAULT_CLOUD_SQL_DB_SOURCE_PATH
+	image := tfDriverConfig.Image
+	if len(parent.Spec.IAMUsers) > 0 {
+		// The IAM users need a newer google provider and Terraform 0.12, only AppDBs that use them run the 0.12 config with the IAM users image.
+		srcPath = DEFAULT_CLOUD_SQL_DB_IAM_SOURCE_PATH
+		image = tfDriverConfig.IAMUsersImage
+	}
+
+	manifest, err := tfdriverv1.LoadManifest(srcPath)
 	if err != nil {
-		return tfapply, fmt.Errorf("Error loading cloud sql DB terraform manifest from %s: %v", DEFAULT_CLOUD_SQL_DB_SOURCE_PATH, err)
+		return tfapply, fmt.Errorf("Error loading cloud sql DB terraform manifest from %s: %v", srcPath, err)
 	}
 
-	tfvars, err := makeTFVars(appdbi.Status.CloudSQL.InstanceName, parent.Spec.DBName, parent.Spec.Users)
+	tfvars, err := makeTFVars(appdbi.Status.CloudSQL.InstanceName, parent.Spec.DBName, parent.Spec.Users, parent.Spec.IAMUsers, appdbi.Spec.Driver.CloudSQLTerraform.Params["database_version"])
 	if err != nil {
 		return tfapply, fmt.Errorf("Failed to generate tfvars from driver config: %v", err)
 	}
 
-	parentSig := calcParentSig(parent.Spec, "")
+	if parent.Spec.GetCredentialsMode() == appdbv1.CredentialsModeVaultDynamic {
+		// Dedicated user for Vault to create and revoke the dynamic users.
+		tfvars["vault_admin_user"] = makeVaultAdminUser(parent)
+	}
+
+	if appdbi.Spec.Driver.CloudSQLTerraform.Connectivity.TLS == true {
+		// Client certificate for the credentials secret.
+		tfvars["client_cert_name"] = fmt.Sprintf("appdb-%s-%s", parent.GetNamespace(), parent.GetName())
+	}
+
+	parentSig := operator.CalcParentSig(parent.Spec, "")
 
 	// Create new object.
 	tfapply = appdbv1.Terraform{
@@ -49,7 +69,7 @@ func makeCloudSQLDBTerraform(tfApplyName string, parent *appdbv1.AppDB, appdbi a
 			},
 		},
 		Spec: tfv1.TerraformSpec{
-			Image:           tfDriverConfig.Image,
+			Image:           image,
 			ImagePullPolicy: tfDriverConfig.ImagePullPolicy,
 			BackendBucket:   tfDriverConfig.BackendBucket,
 			BackendPrefix:   tfDriverConfig.BackendPrefix,
@@ -70,19 +90,7 @@ func makeCloudSQLDBTerraform(tfApplyName string, parent *appdbv1.AppDB, appdbi a
 	return tfapply, nil
 }
 
-func getCloudSQLTerraformManifest(srcPath string) (string, error) {
-	var manifest []byte
-	var err error
-
-	manifest, err = ioutil.ReadFile(srcPath)
-	if err != nil {
-		return string(manifest), err
-	}
-
-	return string(manifest), err
-}
-
-func makeTFVars(instance string, dbname string, users []string) (map[string]string, error) {
+func makeTFVars(instance string, dbname string, users []string, iamUsers []appdbv1.AppDBIAMUser, databaseVersion string) (map[string]string, error) {
 	var tfvars = make(map[string]string, 0)
 
 	tfvars["instance"] = instance
@@ -91,6 +99,17 @@ func makeTFVars(instance string, dbname string, users []string) (map[string]stri
 
 	tfvars["users"] = strings.Join(users, ",")
 
+	if len(iamUsers) > 0 {
+		serviceAccounts := []string{}
+		for _, u := range iamUsers {
+			serviceAccounts = append(serviceAccounts, u.ServiceAccount)
+		}
+		tfvars["iam_users"] = strings.Join(serviceAccounts, ",")
+
+		// The database version determines the IAM database user name format.
+		tfvars["database_version"] = databaseVersion
+	}
+
 	return tfvars, nil
 }
 
@@ -0,0 +1,115 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+)
+
+func reconcileSecretCreated(ctx context.Context, condition *appdbv1.AppDBCondition, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, children *AppDBChildren, desiredChildren *[]interface{}, appdbi appdbv1.AppDBInstance, tfapply tfv1.Terraform) appdbv1.ConditionStatus {
+	newStatus := appdbv1.ConditionFalse
+
+	// TLS certificates are added to each credentials secret when the instance requires SSL.
+	tlsData := make(map[string]string, 0)
+	if appdbi.Spec.Driver.CloudSQLTerraform != nil && appdbi.Spec.Driver.CloudSQLTerraform.Connectivity.TLS == true {
+		for outputVar, key := range map[string]string{"server_ca_cert": "ca.pem", "client_cert": "client-cert.pem", "client_key": "client-key.pem"} {
+			v, ok := tfapply.Status.TFOutput[outputVar]
+			if ok == false {
+				condition.Reason = fmt.Sprintf("No %s found in output varibles of TerraformApply status", outputVar)
+				return newStatus
+			}
+			tlsData[key] = v.Value
+		}
+	}
+
+	if parent.Spec.GetCredentialsMode() == appdbv1.CredentialsModeVaultDynamic {
+		// Generate secret with the Vault role path instead of a password.
+		if status.Vault == nil {
+			condition.Reason = "Vault role has not been configured"
+			return newStatus
+		}
+
+		secretName := fmt.Sprintf("appdb-%s-%s-vault", appdbi.GetName(), parent.GetName())
+
+		secret := makeVaultCredentialsSecret(secretName, parent.GetNamespace(), status.Vault.RolePath, parent.Spec.DBName, appdbi.Status.DBHost, appdbi.Status.DBPort)
+		addSecretData(&secret, tlsData)
+
+		status.CredentialsSecrets = map[string]string{
+			status.Vault.RoleName: secretName,
+		}
+
+		claimChildAndGetCurrent(secret, children, desiredChildren)
+
+		condition.Reason = fmt.Sprintf("Secret/%s: CREATED", secretName)
+
+		return appdbv1.ConditionTrue
+	}
+
+	credentialsSecrets := make(map[string]string, 0)
+	secretNames := []string{}
+
+	// Generate secret for DB credentials.
+	if len(parent.Spec.Users) > 0 {
+		passwordsVar, ok := tfapply.Status.TFOutput["user_passwords"]
+		if ok == false {
+			condition.Reason = "No user_passwords found in output varibles of TerraformApply status"
+			return newStatus
+		}
+
+		passwords := strings.Split(passwordsVar.Value, ",")
+		if len(parent.Spec.Users) != len(passwords) {
+			condition.Reason = fmt.Sprintf("passwords output from TerraformApply is different length than input users.")
+			return newStatus
+		}
+
+		for i := 0; i < len(parent.Spec.Users); i++ {
+			secretName := fmt.Sprintf("appdb-%s-%s-user-%d", appdbi.GetName(), parent.GetName(), i)
+
+			secret := makeCredentialsSecret(secretName, parent.GetNamespace(), parent.Spec.Users[i], passwords[i], parent.Spec.DBName, appdbi.Status.DBHost, appdbi.Status.DBPort)
+			addSecretData(&secret, tlsData)
+
+			secretNames = append(secretNames, secretName)
+
+			credentialsSecrets[parent.Spec.Users[i]] = secretName
+
+			claimChildAndGetCurrent(secret, children, desiredChildren)
+		}
+	}
+
+	// Generate secret for IAM DB users, these have no password.
+	if len(parent.Spec.IAMUsers) > 0 {
+		namesVar, ok := tfapply.Status.TFOutput["iam_user_names"]
+		if ok == false {
+			condition.Reason = "No iam_user_names found in output varibles of TerraformApply status"
+			return newStatus
+		}
+
+		names := strings.Split(namesVar.Value, ",")
+		if len(parent.Spec.IAMUsers) != len(names) {
+			condition.Reason = fmt.Sprintf("iam_user_names output from TerraformApply is different length than input iamUsers.")
+			return newStatus
+		}
+
+		for i := 0; i < len(parent.Spec.IAMUsers); i++ {
+			secretName := fmt.Sprintf("appdb-%s-%s-iam-user-%d", appdbi.GetName(), parent.GetName(), i)
+
+			secret := makeIAMCredentialsSecret(secretName, parent.GetNamespace(), names[i], parent.Spec.IAMUsers[i].ServiceAccount, parent.Spec.DBName, appdbi.Status.DBHost, appdbi.Status.DBPort)
+			addSecretData(&secret, tlsData)
+
+			secretNames = append(secretNames, secretName)
+
+			credentialsSecrets[names[i]] = secretName
+
+			claimChildAndGetCurrent(secret, children, desiredChildren)
+		}
+	}
+
+	status.CredentialsSecrets = credentialsSecrets
+	newStatus = appdbv1.ConditionTrue
+	condition.Reason = fmt.Sprintf("Secret/%s: CREATED", strings.Join(secretNames, ","))
+
+	return newStatus
+}
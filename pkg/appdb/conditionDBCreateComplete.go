@@ -1,18 +1,25 @@
-package main
+package appdb
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/metrics"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	"github.com/danisla/appdb-operator/pkg/tracing"
 	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
-	tfv1 "github.com/danisla/terraform-operator/pkg/types"
 )
 
-func reconcileDBCreateComplete(condition *appdbv1.AppDBCondition, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, children *AppDBChildren, desiredChildren *[]interface{}, appdbi appdbv1.AppDBInstance) (appdbv1.ConditionStatus, tfv1.Terraform) {
+func reconcileDBCreateComplete(ctx context.Context, condition *appdbv1.AppDBCondition, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, children *AppDBChildren, desiredChildren *[]interface{}, appdbi appdbv1.AppDBInstance) (appdbv1.ConditionStatus, tfv1.Terraform) {
 	newStatus := appdbv1.ConditionFalse
 	var tfapply tfv1.Terraform
 
-	if appdbi.Spec.Driver.CloudSQLTerraform != nil {
+	if appdbi.Spec.Driver.CloudSQLTerraform != nil && tfDriverConfig.Enabled() == false {
+		condition.Reason = "The cloudSQLTerraform driver is disabled, set TF_BACKEND_BUCKET or the project of the operator"
+	} else if appdbi.Spec.Driver.CloudSQLTerraform != nil {
 		// Terraform driver
 
 		var ok bool
@@ -23,6 +30,8 @@ func reconcileDBCreateComplete(condition *appdbv1.AppDBCondition, parent *appdbv
 		} else {
 			if tfapply, ok = children.TerraformApplys[tfApplyName]; ok == true {
 				// Already created.
+				newChild.Annotations = tracing.CopyAnnotations(tfapply.Annotations, newChild.Annotations)
+
 				status.CloudSQLDB = &appdbv1.AppDBCloudSQLDBStatus{
 					TFApplyName:    tfapply.GetName(),
 					TFApplyPodName: tfapply.Status.PodName,
@@ -33,6 +42,9 @@ func reconcileDBCreateComplete(condition *appdbv1.AppDBCondition, parent *appdbv
 
 				if tfapply.Status.PodStatus == tfv1.PodStatusPassed {
 					newStatus = appdbv1.ConditionTrue
+					if condition.Status != appdbv1.ConditionTrue {
+						tfdriverv1.RecordSpan(ctx, "TerraformApply", tfapply)
+					}
 					claimChildAndGetCurrent(newChild, children, desiredChildren)
 				} else if tfapply.Status.PodStatus == tfv1.PodStatusFailed {
 					condition.Reason = fmt.Sprintf("TerraformApply/%s pod failed", tfapply.GetName())
@@ -44,7 +56,9 @@ func reconcileDBCreateComplete(condition *appdbv1.AppDBCondition, parent *appdbv
 					} else {
 						condition.Message = "Retry in 60 seconds"
 						if time.Since(tfapplyFishedAtTime).Seconds() > 60 {
-							parent.Log("Retrying TerraformApply,%s", tfapply.GetName())
+							logging.FromContext(ctx).WithChild("TerraformApply", tfapply.GetName()).Infof("Retrying TerraformApply")
+							metrics.IncTerraformRetry("TerraformApply", "AppDB")
+							tfdriverv1.RecordSpan(ctx, "TerraformApply", tfapply)
 						} else {
 							claimChildAndGetCurrent(newChild, children, desiredChildren)
 						}
@@ -55,6 +69,7 @@ func reconcileDBCreateComplete(condition *appdbv1.AppDBCondition, parent *appdbv
 				}
 			} else {
 				// Not yet created.
+				newChild.Annotations = tracing.InjectAnnotations(ctx, newChild.Annotations)
 				claimChildAndGetCurrent(newChild, children, desiredChildren)
 			}
 		}
@@ -0,0 +1,79 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+)
+
+func reconcileAppDBIReady(ctx context.Context, condition *appdbv1.AppDBCondition, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, children *AppDBChildren, desiredChildren *[]interface{}) (appdbv1.ConditionStatus, appdbv1.AppDBInstance) {
+	newStatus := appdbv1.ConditionFalse
+	var appdbi appdbv1.AppDBInstance
+
+	if parent.Spec.InstanceClassName != "" && status.AppDBInstance == "" {
+		placement, err := selectAppDBInstanceForClass(ctx, parent)
+		if err != nil {
+			condition.Reason = err.Error()
+			return newStatus, appdbi
+		}
+		logging.FromContext(ctx).Infof("Placed on AppDBInstance/%s of AppDBInstanceClass/%s, instance load: %d", placement.AppDBInstance, placement.InstanceClassName, placement.InstanceLoad)
+		status.AppDBInstance = placement.AppDBInstance
+		status.Placement = &placement
+	}
+
+	ref := status.AppDBInstance
+	if parent.Spec.AppDBInstance != "" {
+		ref = parent.Spec.AppDBInstance
+	}
+	appdbiNamespace, appdbiName := appdbv1.ParseAppDBInstanceRef(ref, parent.GetNamespace())
+	status.AppDBInstance = fmt.Sprintf("%s/%s", appdbiNamespace, appdbiName)
+
+	appdbi, err := config.KubeClient.GetAppDBInstance(appdbiNamespace, appdbiName)
+	if err == nil {
+		if err := checkAppDBInstanceAllowed(appdbi, parent.GetNamespace()); err != nil {
+			condition.Reason = err.Error()
+		} else if appdbiNamespace != parent.GetNamespace() && appdbi.Spec.Driver.CloudSQLTerraform != nil && appdbi.Spec.Driver.CloudSQLTerraform.Proxy.GetMode() == appdbv1.CloudSQLProxyModeSidecar && appdbi.Spec.Driver.CloudSQLTerraform.Proxy.GetAuth() == appdbv1.CloudSQLProxyAuthServiceAccountKey {
+			// The sidecar cannot mount the proxy service account key secret from the namespace of the instance.
+			condition.Reason = fmt.Sprintf("AppDBInstance/%s: proxy mode %s with auth %s is not supported from other namespaces", status.AppDBInstance, appdbv1.CloudSQLProxyModeSidecar, appdbv1.CloudSQLProxyAuthServiceAccountKey)
+		} else if err := checkAppDBInstanceCapacity(parent, status, appdbi); err != nil {
+			condition.Reason = err.Error()
+		} else if status.AppDBInstanceSig != "" && status.AppDBInstanceSig != operator.CalcParentSig(appdbi.Spec, "") {
+			// AppDBInstance spec changed.
+			condition.Reason = fmt.Sprintf("AppDBInstance/%s change detected", appdbi.GetName())
+		} else {
+			if appdbi.Status.Provisioning == appdbv1.ProvisioningStatusComplete {
+				newStatus = appdbv1.ConditionTrue
+				status.AppDBInstanceSig = operator.CalcParentSig(appdbi.Spec, "")
+				status.DBHost = appdbi.Status.DBHost
+			}
+			condition.Reason = fmt.Sprintf("AppDBInstance/%s: %s", appdbi.GetName(), appdbi.Status.Provisioning)
+		}
+	} else {
+		condition.Reason = fmt.Sprintf("AppDBInstance/%s: Not found", status.AppDBInstance)
+	}
+
+	return newStatus, appdbi
+}
+
+// checkAppDBInstanceCapacity returns an error if an AppDB pinned with spec.appDBInstance does not fit on the instance.
+// AppDBs placed by an AppDBInstanceClass, or with a database already created, are not checked.
+func checkAppDBInstanceCapacity(parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, appdbi appdbv1.AppDBInstance) error {
+	if parent.Spec.AppDBInstance == "" || appdbi.Spec.MaxDatabases == 0 || status.CloudSQLDB != nil {
+		return nil
+	}
+
+	appdbs, err := config.KubeClient.ListAppDBs()
+	if err != nil {
+		return fmt.Errorf("Failed to list AppDBs: %v", err)
+	}
+
+	_, load := getAppDBInstanceLoad(parent, appdbs, appdbi)
+	if load+parent.Spec.GetSizeHint() > appdbi.Spec.MaxDatabases {
+		return fmt.Errorf("AppDBInstance/%s is at capacity: %d/%d, sizeHint: %d", makeAppDBInstanceRef(appdbi), load, appdbi.Spec.MaxDatabases, parent.Spec.GetSizeHint())
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/metrics"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+func reconcileSnapshotLoadComplete(ctx context.Context, condition *appdbv1.AppDBCondition, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, children *AppDBChildren, desiredChildren *[]interface{}, appdbi appdbv1.AppDBInstance) appdbv1.ConditionStatus {
+	newStatus := appdbv1.ConditionFalse
+	jobName := fmt.Sprintf("appdb-%s-%s-load", appdbi.GetName(), parent.GetName())
+	loadURL, err := makeLoadURL(parent.Spec.LoadURL)
+	if err != nil {
+		condition.Reason = err.Error()
+		return newStatus
+	}
+	job := makeLoadJob(jobName, parent.GetNamespace(), appdbi.Status.CloudSQL.InstanceName, loadURL, parent.Spec.DBName, parent.Spec.Users[0], appdbi.Status.CloudSQL.ServiceAccountEmail)
+	if currJob, ok := children.Jobs[job.GetName()]; ok == true {
+		job.Annotations = tracing.CopyAnnotations(currJob.Annotations, job.Annotations)
+
+		// Wait for load job to complete.
+		if currJob.Status.Succeeded == 1 {
+			// load complete.
+			newStatus = appdbv1.ConditionTrue
+			if condition.Status != appdbv1.ConditionTrue && currJob.Status.StartTime != nil && currJob.Status.CompletionTime != nil {
+				// Only observed on the transition to avoid counting the job on every sync.
+				metrics.ObserveLoadJob("succeeded", currJob.Status.CompletionTime.Sub(currJob.Status.StartTime.Time))
+				recordLoadJobSpan(ctx, currJob, currJob.Status.CompletionTime.Time, "succeeded")
+			}
+			claimChildAndGetCurrent(job, children, desiredChildren)
+		} else if currJob.Status.Failed == *currJob.Spec.BackoffLimit {
+			// Requeue job
+			logging.FromContext(ctx).WithChild("Job", currJob.GetName()).Infof("Recreating SQL load job")
+			if currJob.Status.StartTime != nil {
+				metrics.ObserveLoadJob("failed", time.Since(currJob.Status.StartTime.Time))
+				recordLoadJobSpan(ctx, currJob, time.Now(), "failed")
+			}
+		} else {
+			claimChildAndGetCurrent(job, children, desiredChildren)
+		}
+	} else {
+		// Create job
+		job.Annotations = tracing.InjectAnnotations(ctx, job.Annotations)
+		claimChildAndGetCurrent(job, children, desiredChildren)
+		logging.FromContext(ctx).WithChild("Job", job.GetName()).Infof("Created SQL load job from snapshot %s", loadURL)
+	}
+
+	return newStatus
+}
+
+// recordLoadJobSpan records the run of the load job as a span of the trace of the sync that created the job.
+func recordLoadJobSpan(ctx context.Context, job batchv1.Job, end time.Time, result string) {
+	tracing.RecordSpan(tracing.ContextFromAnnotations(ctx, job.Annotations), "SQL load job", job.Status.StartTime.Time, end,
+		append(tracing.ObjectAttributes("Job", job.GetNamespace(), job.GetName()), attribute.String("appdb.load_job.result", result))...)
+}
+
+// makeLoadURL returns the gs:// URL of the snapshot, relative URLs are in the bucket of the Terraform driver.
+func makeLoadURL(loadURL string) (string, error) {
+	if strings.HasPrefix(loadURL, "gs://") {
+		return loadURL, nil
+	}
+	if tfDriverConfig == nil || tfDriverConfig.Enabled() == false {
+		return "", fmt.Errorf("spec.loadURL %s is relative to the bucket of the cloudSQLTerraform driver, but the driver is disabled, use a gs:// URL", loadURL)
+	}
+	return fmt.Sprintf("gs://%s/%s", tfDriverConfig.BackendBucket, strings.TrimPrefix(loadURL, "/")), nil
+}
@@ -0,0 +1,36 @@
+package appdb
+
+import (
+	"testing"
+
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+)
+
+func TestMakeLoadURL(t *testing.T) {
+	defer func(c *tfdriverv1.TerraformDriverConfig) { tfDriverConfig = c }(tfDriverConfig)
+
+	tests := []struct {
+		name    string
+		bucket  string
+		loadURL string
+		want    string
+		wantErr bool
+	}{
+		{"absolute", "", "gs://snapshots/sample.sql", "gs://snapshots/sample.sql", false},
+		{"relative", "my-project-appdb-operator", "sample.sql", "gs://my-project-appdb-operator/sample.sql", false},
+		{"relative with slash", "my-project-appdb-operator", "/dumps/sample.sql", "gs://my-project-appdb-operator/dumps/sample.sql", false},
+		{"short relative", "my-project-appdb-operator", "a.gz", "gs://my-project-appdb-operator/a.gz", false},
+		{"relative with driver disabled", "", "sample.sql", "", true},
+	}
+
+	for _, tc := range tests {
+		tfDriverConfig = &tfdriverv1.TerraformDriverConfig{BackendBucket: tc.bucket}
+		got, err := makeLoadURL(tc.loadURL)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error %v, got: %v", tc.name, tc.wantErr, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected %q, got: %q", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+)
+
+func reconcileVaultRoleConfigured(ctx context.Context, condition *appdbv1.AppDBCondition, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus, children *AppDBChildren, desiredChildren *[]interface{}, appdbi appdbv1.AppDBInstance, tfapply tfv1.Terraform) appdbv1.ConditionStatus {
+	newStatus := appdbv1.ConditionFalse
+
+	// The dedicated admin user created by the TerraformApply is used by Vault to manage the dynamic users.
+	passwordVar, ok := tfapply.Status.TFOutput["vault_admin_password"]
+	if ok == false || passwordVar.Value == "" {
+		condition.Reason = "No vault_admin_password found in output variables of TerraformApply status"
+		return newStatus
+	}
+
+	connectionName := makeVaultConnectionName(parent, appdbi)
+	roleName := makeVaultRoleName(parent, appdbi)
+	vaultSpec := parent.Spec.Credentials.Vault
+
+	mount := ""
+	if vaultSpec != nil {
+		mount = vaultSpec.Mount
+	}
+
+	conn, err := makeVaultDatabaseConnection(roleName, makeVaultAdminUser(parent), passwordVar.Value, parent.Spec.DBName, appdbi)
+	if err != nil {
+		condition.Reason = fmt.Sprintf("Failed to make Vault database connection: %v", err)
+		return newStatus
+	}
+
+	role, err := makeVaultDatabaseRole(connectionName, parent.Spec.DBName, vaultSpec, appdbi)
+	if err != nil {
+		condition.Reason = fmt.Sprintf("Failed to make Vault database role: %v", err)
+		return newStatus
+	}
+
+	// Only write to Vault when the config changes.
+	configSig := operator.CalcParentSig(struct {
+		Mount      string
+		Connection interface{}
+		Role       interface{}
+	}{mount, conn, role}, "")
+
+	if status.Vault == nil || status.Vault.ConfigSig != configSig {
+		_, span := tracing.Start(ctx, "vault WriteDatabaseConnection")
+		err = vaultClient.WriteDatabaseConnection(mount, connectionName, conn)
+		tracing.End(span, err)
+		if err != nil {
+			condition.Reason = fmt.Sprintf("Failed to write Vault database connection %s: %v", connectionName, err)
+			return newStatus
+		}
+
+		_, span = tracing.Start(ctx, "vault WriteDatabaseRole")
+		err = vaultClient.WriteDatabaseRole(mount, roleName, role)
+		tracing.End(span, err)
+		if err != nil {
+			condition.Reason = fmt.Sprintf("Failed to write Vault database role %s: %v", roleName, err)
+			return newStatus
+		}
+
+		logging.FromContext(ctx).Infof("Configured Vault database connection %s and role %s", connectionName, roleName)
+
+		// The role was renamed, by a change of spec.credentials.vault or of the role name format, delete the previous role.
+		if status.Vault != nil && status.Vault.RoleName != "" && status.Vault.RolePath != vaultClient.RolePath(mount, roleName) {
+			prevMount := strings.TrimSuffix(status.Vault.RolePath, fmt.Sprintf("/creds/%s", status.Vault.RoleName))
+			_, span = tracing.Start(ctx, "vault DeleteDatabaseRole")
+			err = vaultClient.DeleteDatabaseRole(prevMount, status.Vault.RoleName)
+			tracing.End(span, err)
+			if err != nil {
+				condition.Reason = fmt.Sprintf("Failed to delete previous Vault database role %s: %v", status.Vault.RoleName, err)
+				return newStatus
+			}
+			logging.FromContext(ctx).Infof("Deleted previous Vault database role %s", status.Vault.RoleName)
+		}
+	}
+
+	status.Vault = &appdbv1.AppDBVaultStatus{
+		ConnectionName: connectionName,
+		RoleName:       roleName,
+		RolePath:       vaultClient.RolePath(mount, roleName),
+		ConfigSig:      configSig,
+	}
+
+	newStatus = appdbv1.ConditionTrue
+	condition.Reason = fmt.Sprintf("Vault role %s: CONFIGURED", status.Vault.RolePath)
+
+	return newStatus
+}
@@ -0,0 +1,189 @@
+package appdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	vaultv1 "github.com/danisla/appdb-operator/pkg/vault"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakeVault is a Vault HTTP server that keeps the paths written by the client, the client waits for each response so no lock is needed.
+type fakeVault struct {
+	paths map[string]bool
+}
+
+func (fv *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch r.Method {
+	case "POST":
+		fv.paths[path] = true
+	case "DELETE":
+		delete(fv.paths, path)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (fv *fakeVault) has(path string) bool {
+	return fv.paths[path]
+}
+
+func setupVaultTest(t *testing.T) *fakeVault {
+	fv := &fakeVault{paths: make(map[string]bool, 0)}
+	srv := httptest.NewServer(fv)
+
+	client, err := vaultv1.NewClient(&vaultv1.VaultConfig{Address: srv.URL, Token: "root", DatabaseMount: vaultv1.DEFAULT_VAULT_DATABASE_MOUNT})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	prevClient := vaultClient
+	vaultClient = client
+	t.Cleanup(func() {
+		srv.Close()
+		vaultClient = prevClient
+	})
+
+	return fv
+}
+
+func newTestVaultAppDB(name, uid, roleName string) *appdbv1.AppDB {
+	parent := newTestAppDB(name)
+	parent.UID = types.UID(uid)
+	parent.Spec.Credentials = &appdbv1.AppDBCredentialsSpec{
+		Mode:  appdbv1.CredentialsModeVaultDynamic,
+		Vault: &appdbv1.AppDBVaultCredentialsSpec{RoleName: roleName},
+	}
+	return parent
+}
+
+func newTestVaultAppDBInstance(databaseVersion string, connectivity appdbv1.CloudSQLConnectivityMode) appdbv1.AppDBInstance {
+	return appdbv1.AppDBInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"},
+		Spec: appdbv1.AppDBInstanceSpec{
+			Driver: appdbv1.AppDBDriver{
+				CloudSQLTerraform: &appdbv1.AppDBCloudSQLTerraformDriver{
+					Params:       map[string]string{"database_version": databaseVersion},
+					Connectivity: appdbv1.CloudSQLConnectivitySpec{Mode: connectivity},
+				},
+			},
+		},
+		Status: appdbv1.AppDBInstanceOperatorStatus{DBHost: "example-proxy.default.svc.cluster.local", DBPort: 3306},
+	}
+}
+
+func newTestVaultTFApply() tfv1.Terraform {
+	return tfv1.Terraform{
+		Status: tfv1.TerraformOperatorStatus{
+			TFOutput: map[string]tfv1.TerraformOutputVar{
+				"vault_admin_password": tfv1.TerraformOutputVar{Value: "secret"},
+			},
+		},
+	}
+}
+
+func reconcileTestVaultRole(t *testing.T, parent *appdbv1.AppDB, status *appdbv1.AppDBOperatorStatus) {
+	condition := &appdbv1.AppDBCondition{Type: appdbv1.ConditionTypeVaultRoleConfigured}
+	desiredChildren := make([]interface{}, 0)
+	appdbi := newTestVaultAppDBInstance("MYSQL_5_7", "")
+
+	got := reconcileVaultRoleConfigured(context.Background(), condition, parent, status, &AppDBChildren{}, &desiredChildren, appdbi, newTestVaultTFApply())
+	if got != appdbv1.ConditionTrue {
+		t.Fatalf("Expected condition True, got: %s: %s", got, condition.Reason)
+	}
+}
+
+func TestReconcileVaultRoleConfigured(t *testing.T) {
+	fv := setupVaultTest(t)
+
+	parent := newTestVaultAppDB("a", "uid-a", "")
+	status := &appdbv1.AppDBOperatorStatus{}
+	reconcileTestVaultRole(t, parent, status)
+
+	if status.Vault == nil || status.Vault.RolePath != "database/creds/appdb-default-example-a" {
+		t.Fatalf("Expected role path database/creds/appdb-default-example-a, got: %+v", status.Vault)
+	}
+	if fv.has("database/roles/appdb-default-example-a") == false || fv.has("database/config/appdb-default-example-a") == false {
+		t.Errorf("Expected role and connection to be written, got: %v", fv.paths)
+	}
+
+	// Renaming the role deletes the previous one.
+	parent.Spec.Credentials.Vault.RoleName = "app"
+	reconcileTestVaultRole(t, parent, status)
+
+	if status.Vault.RoleName != "default-app-uid-a" {
+		t.Errorf("Expected role default-app-uid-a, got: %s", status.Vault.RoleName)
+	}
+	if fv.has("database/roles/default-app-uid-a") == false {
+		t.Errorf("Expected renamed role to be written, got: %v", fv.paths)
+	}
+	if fv.has("database/roles/appdb-default-example-a") == true {
+		t.Errorf("Expected previous role to be deleted, got: %v", fv.paths)
+	}
+}
+
+func TestReconcileVaultRoleConfiguredSameRoleName(t *testing.T) {
+	fv := setupVaultTest(t)
+
+	// Two AppDBs in the same namespace with the same roleName do not write the same role.
+	a, b := newTestVaultAppDB("a", "uid-a", "app"), newTestVaultAppDB("b", "uid-b", "app")
+	statusA, statusB := &appdbv1.AppDBOperatorStatus{}, &appdbv1.AppDBOperatorStatus{}
+	reconcileTestVaultRole(t, a, statusA)
+	reconcileTestVaultRole(t, b, statusB)
+
+	if statusA.Vault.RolePath == statusB.Vault.RolePath {
+		t.Fatalf("Expected different role paths, got: %s", statusA.Vault.RolePath)
+	}
+	if fv.has("database/roles/default-app-uid-a") == false || fv.has("database/roles/default-app-uid-b") == false {
+		t.Errorf("Expected a role per AppDB, got: %v", fv.paths)
+	}
+}
+
+func TestDeleteVaultRole(t *testing.T) {
+	fv := setupVaultTest(t)
+
+	parent := newTestVaultAppDB("a", "uid-a", "app")
+	reconcileTestVaultRole(t, parent, &parent.Status)
+
+	if err := deleteVaultRole(context.Background(), parent); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fv.paths) != 0 {
+		t.Errorf("Expected role and connection to be deleted, got: %v", fv.paths)
+	}
+}
+
+func TestMakeVaultDatabaseConnectionURL(t *testing.T) {
+	tests := []struct {
+		name            string
+		databaseVersion string
+		connectivity    appdbv1.CloudSQLConnectivityMode
+		want            string
+	}{
+		{"mysql proxy", "MYSQL_5_7", appdbv1.CloudSQLConnectivityModeProxy, "{{username}}:{{password}}@tcp(db:5432)/"},
+		{"mysql private IP", "MYSQL_5_7", appdbv1.CloudSQLConnectivityModePrivateIP, "{{username}}:{{password}}@tcp(db:5432)/?tls=skip-verify"},
+		{"postgres proxy", "POSTGRES_9_6", appdbv1.CloudSQLConnectivityModeProxy, "postgresql://{{username}}:{{password}}@db:5432/app?sslmode=disable"},
+		{"postgres private IP proxy", "POSTGRES_9_6", appdbv1.CloudSQLConnectivityModePrivateIPProxy, "postgresql://{{username}}:{{password}}@db:5432/app?sslmode=disable"},
+		{"postgres private IP", "POSTGRES_9_6", appdbv1.CloudSQLConnectivityModePrivateIP, "postgresql://{{username}}:{{password}}@db:5432/app?sslmode=require"},
+	}
+
+	for _, tc := range tests {
+		appdbi := newTestVaultAppDBInstance(tc.databaseVersion, tc.connectivity)
+		appdbi.Status.DBHost = "db"
+		appdbi.Status.DBPort = 5432
+
+		conn, err := makeVaultDatabaseConnection("role", "vault", "secret", "app", appdbi)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if conn.ConnectionURL != tc.want {
+			t.Errorf("%s: expected %q, got: %q", tc.name, tc.want, conn.ConnectionURL)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func makeConditionOrder(parent *appdbv1.AppDB) []appdbv1.AppDBConditionType {
+	conditionOrder := make([]appdbv1.AppDBConditionType, 0)
+	for _, c := range ConditionStatusOrder {
+		if c == appdbv1.ConditionTypeSnapshotLoadComplete && parent.Spec.LoadURL == "" {
+			// Skip condition.
+			continue
+		}
+		if c == appdbv1.ConditionTypeVaultRoleConfigured && parent.Spec.GetCredentialsMode() != appdbv1.CredentialsModeVaultDynamic {
+			// Skip condition.
+			continue
+		}
+		conditionOrder = append(conditionOrder, c)
+	}
+	return conditionOrder
+}
+
+func checkConditions(checkType appdbv1.AppDBConditionType, conditions map[appdbv1.AppDBConditionType]*appdbv1.AppDBCondition) error {
+	waiting := []string{}
+
+	for _, conditionType := range conditionDependencies[checkType] {
+		condition, ok := conditions[conditionType]
+		if ok == false {
+			// Dependency was skipped by makeConditionOrder.
+			continue
+		}
+		if condition.Status != appdbv1.ConditionTrue {
+			waiting = append(waiting, string(conditionType))
+		}
+	}
+
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Waiting on conditions: %s", strings.Join(waiting, ","))
+}
+
+// recordConditionTransition emits an Event on the parent for a condition changing to newStatus, the Event is a Warning when a condition is no longer True.
+// The time the condition spent in its previous status is recorded as a span.
+func recordConditionTransition(ctx context.Context, parent *appdbv1.AppDB, condition *appdbv1.AppDBCondition, newStatus appdbv1.ConditionStatus) {
+	tracing.RecordSpan(ctx, fmt.Sprintf("condition %s", condition.Type), condition.LastTransitionTime.Time, time.Now(),
+		attribute.String("appdb.condition", string(condition.Type)),
+		attribute.String("appdb.condition.status", string(condition.Status)),
+		attribute.String("appdb.condition.next_status", string(newStatus)),
+	)
+
+	eventType := corev1.EventTypeNormal
+	if condition.Status == appdbv1.ConditionTrue {
+		eventType = corev1.EventTypeWarning
+	}
+
+	msg := fmt.Sprintf("Condition %s changed from %s to %s", condition.Type, condition.Status, newStatus)
+	if condition.Reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, condition.Reason)
+	}
+	if condition.Message != "" {
+		msg = fmt.Sprintf("%s, %s", msg, condition.Message)
+	}
+
+	eventRecorder.Transition(parent, string(condition.Type), string(newStatus), eventType, string(condition.Type), "%s", msg)
+}
@@ -0,0 +1,74 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danisla/appdb-operator/pkg/hook"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+)
+
+// Finalize is the finalize hook of the AppDB controller, it deletes the Vault role and connection of the AppDB before the AppDB is deleted,
+// and releases the capacity reserved on the instance of its AppDBInstanceClass, marking the instance reclaimable if it was created for the class and is now empty.
+// Errors are retried, the status and children are kept and the children are garbage collected after the AppDB is finalized.
+func Finalize(ctx context.Context, request *hook.SyncRequest) (*hook.SyncResponse, error) {
+	var req SyncRequest
+	if err := request.Decode(&req); err != nil {
+		return nil, fmt.Errorf("Could not convert SyncRequest: %v", err)
+	}
+
+	if err := deleteVaultRole(ctx, &req.Parent); err != nil {
+		logging.FromContext(ctx).Errorf("Could not finalize: %v", err)
+		return nil, err
+	}
+
+	if err := releaseAppDBInstance(ctx, &req.Parent); err != nil {
+		logging.FromContext(ctx).Errorf("Could not finalize: %v", err)
+		return nil, err
+	}
+
+	return &hook.SyncResponse{
+		Status:    request.ParentStatus(),
+		Children:  request.ObservedChildren(),
+		Finalized: true,
+	}, nil
+}
+
+// deleteVaultRole deletes the Vault role and connection recorded in the status of the AppDB.
+func deleteVaultRole(ctx context.Context, parent *appdbv1.AppDB) error {
+	logger := logging.FromContext(ctx)
+
+	if parent.Status.Vault == nil || parent.Status.Vault.RoleName == "" {
+		return nil
+	}
+	if vaultClient == nil {
+		logger.Warnf("Operator was started without VAULT_ADDR, Vault role %s and connection %s are not deleted", parent.Status.Vault.RolePath, parent.Status.Vault.ConnectionName)
+		return nil
+	}
+
+	// The role path is <mount>/creds/<role>, the mount in the spec may have changed since the role was written.
+	mount := strings.TrimSuffix(parent.Status.Vault.RolePath, fmt.Sprintf("/creds/%s", parent.Status.Vault.RoleName))
+
+	_, span := tracing.Start(ctx, "vault DeleteDatabaseRole")
+	err := vaultClient.DeleteDatabaseRole(mount, parent.Status.Vault.RoleName)
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("Failed to delete Vault database role %s: %v", parent.Status.Vault.RoleName, err)
+	}
+
+	if parent.Status.Vault.ConnectionName != "" {
+		_, span = tracing.Start(ctx, "vault DeleteDatabaseConnection")
+		err = vaultClient.DeleteDatabaseConnection(mount, parent.Status.Vault.ConnectionName)
+		tracing.End(span, err)
+		if err != nil {
+			return fmt.Errorf("Failed to delete Vault database connection %s: %v", parent.Status.Vault.ConnectionName, err)
+		}
+	}
+
+	logger.Infof("Deleted Vault database role %s and connection %s", parent.Status.Vault.RoleName, parent.Status.Vault.ConnectionName)
+
+	return nil
+}
@@ -0,0 +1,391 @@
+package appdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// DEFAULT_CLASS_INSTANCE_CREATE_ATTEMPTS is the number of names tried when creating an instance of a class collides with instances created concurrently.
+	DEFAULT_CLASS_INSTANCE_CREATE_ATTEMPTS = 5
+)
+
+// errInstanceUnavailable is returned when the capacity of a class instance cannot be reserved for the AppDB, another instance is tried.
+type errInstanceUnavailable struct {
+	msg string
+}
+
+func (e errInstanceUnavailable) Error() string {
+	return e.msg
+}
+
+// getAppDBInstanceLoad returns the number of AppDBs, other than the parent, placed on the AppDBInstance and the sum of their size hints.
+// AppDBs with a reservation on the instance are counted before their status.appDBInstance is written.
+func getAppDBInstanceLoad(parent *appdbv1.AppDB, appdbs []appdbv1.AppDB, appdbi appdbv1.AppDBInstance) (int32, int32) {
+	var count int32
+	var load int32
+
+	counted := make(map[string]bool, 0)
+	for _, appdb := range appdbs {
+		if appdb.GetNamespace() == parent.GetNamespace() && appdb.GetName() == parent.GetName() {
+			continue
+		}
+		namespace, name := appdb.GetAppDBInstanceRef()
+		if namespace == appdbi.GetNamespace() && name == appdbi.GetName() {
+			count++
+			load += appdb.Spec.GetSizeHint()
+			counted[makeAppDBKey(&appdb)] = true
+		}
+	}
+
+	for key, sizeHint := range getReservations(appdbi) {
+		if key == makeAppDBKey(parent) || counted[key] == true {
+			continue
+		}
+		count++
+		load += sizeHint
+	}
+
+	return count, load
+}
+
+// selectAppDBInstanceForClass places the AppDB on the least-loaded AppDBInstance of the class with capacity for its size hint, creating a new instance if none fits.
+// The capacity is reserved on the instance before the placement is returned, see reserveAppDBInstance.
+func selectAppDBInstanceForClass(ctx context.Context, parent *appdbv1.AppDB) (appdbv1.AppDBPlacementStatus, error) {
+	placement := appdbv1.AppDBPlacementStatus{
+		InstanceClassName: parent.Spec.InstanceClassName,
+		SizeHint:          parent.Spec.GetSizeHint(),
+	}
+
+	class, err := config.KubeClient.GetAppDBInstanceClass(parent.Spec.InstanceClassName)
+	if err != nil {
+		return placement, fmt.Errorf("AppDBInstanceClass/%s: Not found", parent.Spec.InstanceClassName)
+	}
+
+	instances, err := config.KubeClient.ListAppDBInstances(labels.SelectorFromSet(labels.Set{appdbv1.AppDBInstanceClassLabel: class.GetName()}))
+	if err != nil {
+		return placement, fmt.Errorf("Failed to list AppDBInstances of class %s: %v", class.GetName(), err)
+	}
+
+	appdbs, err := config.KubeClient.ListAppDBs()
+	if err != nil {
+		return placement, fmt.Errorf("Failed to list AppDBs: %v", err)
+	}
+
+	type candidate struct {
+		appdbi appdbv1.AppDBInstance
+		load   int32
+	}
+	candidates := []candidate{}
+
+	for _, appdbi := range instances {
+		if checkAppDBInstanceAllowed(appdbi, parent.GetNamespace()) != nil || isInstanceReclaimable(appdbi) {
+			continue
+		}
+
+		_, load := getAppDBInstanceLoad(parent, appdbs, appdbi)
+		if maxDatabases := getClassInstanceMaxDatabases(class, appdbi); maxDatabases > 0 && load+placement.SizeHint > maxDatabases {
+			continue
+		}
+
+		candidates = append(candidates, candidate{appdbi, load})
+	}
+
+	// Least-loaded instance wins, ties are broken by namespace/name for stable placement.
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].load != candidates[j].load {
+			return candidates[i].load < candidates[j].load
+		}
+		return makeAppDBInstanceRef(candidates[i].appdbi) < makeAppDBInstanceRef(candidates[j].appdbi)
+	})
+
+	for _, c := range candidates {
+		load, err := reserveAppDBInstance(ctx, parent, class, appdbs, c.appdbi.GetNamespace(), c.appdbi.GetName())
+		if _, ok := err.(errInstanceUnavailable); ok == true {
+			// Filled up or marked reclaimable since the cache was read.
+			logging.FromContext(ctx).Infof("Skipping AppDBInstance/%s: %v", makeAppDBInstanceRef(c.appdbi), err)
+			continue
+		}
+		if err != nil {
+			return placement, err
+		}
+
+		placement.AppDBInstance = makeAppDBInstanceRef(c.appdbi)
+		placement.InstanceLoad = load
+		return placement, nil
+	}
+
+	// No instance with capacity, create a new one.
+	// Names are <class>-<index>, the first free index is used so that concurrent creates for the same class collide instead of creating several instances.
+	attempts := 0
+	for index := 0; attempts < DEFAULT_CLASS_INSTANCE_CREATE_ATTEMPTS; index++ {
+		appdbi := makeAppDBInstanceFromClass(class, parent, index)
+
+		if _, err := config.KubeClient.GetAppDBInstance(appdbi.GetNamespace(), appdbi.GetName()); err == nil {
+			// Name in use by a candidate or by an instance that is not of the class.
+			continue
+		}
+		attempts++
+
+		_, span := tracing.Start(ctx, "kube CreateAppDBInstance", tracing.ObjectAttributes("AppDBInstance", appdbi.GetNamespace(), appdbi.GetName())...)
+		err = config.KubeClient.CreateAppDBInstance(appdbi)
+		tracing.End(span, err)
+
+		if err == nil {
+			logging.FromContext(ctx).WithChild("AppDBInstance", makeAppDBInstanceRef(appdbi)).Infof("Created AppDBInstance from AppDBInstanceClass/%s", class.GetName())
+
+			placement.AppDBInstance = makeAppDBInstanceRef(appdbi)
+			placement.InstanceLoad = placement.SizeHint
+			placement.Created = true
+			return placement, nil
+		}
+
+		if apierrors.IsAlreadyExists(err) == false {
+			return placement, fmt.Errorf("Failed to create AppDBInstance/%s of class %s: %v", makeAppDBInstanceRef(appdbi), class.GetName(), err)
+		}
+
+		// Created concurrently, by the sync of another AppDB or by a previous sync of this AppDB, place the AppDB on it if it fits.
+		load, err := reserveAppDBInstance(ctx, parent, class, appdbs, appdbi.GetNamespace(), appdbi.GetName())
+		if _, ok := err.(errInstanceUnavailable); ok == true {
+			continue
+		}
+		if err != nil {
+			return placement, err
+		}
+
+		placement.AppDBInstance = makeAppDBInstanceRef(appdbi)
+		placement.InstanceLoad = load
+		return placement, nil
+	}
+
+	return placement, fmt.Errorf("Failed to create AppDBInstance of class %s after %d attempts, instances are created concurrently", class.GetName(), DEFAULT_CLASS_INSTANCE_CREATE_ATTEMPTS)
+}
+
+// reserveAppDBInstance adds the size hint of the AppDB to the reservations of the class instance and returns the load of the instance including the AppDB.
+// The instance is read from the API server and updated with its resourceVersion, concurrent reservations conflict and are retried with the new load.
+// An errInstanceUnavailable is returned if the AppDB does not fit or the instance is not of the class or is reclaimable.
+func reserveAppDBInstance(ctx context.Context, parent *appdbv1.AppDB, class appdbv1.AppDBInstanceClass, appdbs []appdbv1.AppDB, namespace, name string) (int32, error) {
+	var load int32
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		appdbi, err := config.KubeClient.FetchAppDBInstance(namespace, name)
+		if err != nil {
+			return err
+		}
+
+		if appdbi.Labels[appdbv1.AppDBInstanceClassLabel] != class.GetName() {
+			return errInstanceUnavailable{fmt.Sprintf("AppDBInstance/%s/%s is not of class %s", namespace, name, class.GetName())}
+		}
+		if isInstanceReclaimable(appdbi) {
+			return errInstanceUnavailable{fmt.Sprintf("AppDBInstance/%s/%s is reclaimable or being deleted", namespace, name)}
+		}
+		if err := checkAppDBInstanceAllowed(appdbi, parent.GetNamespace()); err != nil {
+			return errInstanceUnavailable{err.Error()}
+		}
+
+		_, otherLoad := getAppDBInstanceLoad(parent, appdbs, appdbi)
+		load = otherLoad + parent.Spec.GetSizeHint()
+		if maxDatabases := getClassInstanceMaxDatabases(class, appdbi); maxDatabases > 0 && load > maxDatabases {
+			return errInstanceUnavailable{fmt.Sprintf("AppDBInstance/%s/%s is at capacity: %d/%d, sizeHint: %d", namespace, name, otherLoad, maxDatabases, parent.Spec.GetSizeHint())}
+		}
+
+		reservations := pruneReservations(getReservations(appdbi), appdbs)
+		if sizeHint, ok := reservations[makeAppDBKey(parent)]; ok == true && sizeHint == parent.Spec.GetSizeHint() {
+			// Already reserved by a previous sync.
+			return nil
+		}
+		reservations[makeAppDBKey(parent)] = parent.Spec.GetSizeHint()
+
+		if err := setReservations(&appdbi, reservations); err != nil {
+			return err
+		}
+
+		_, span := tracing.Start(ctx, "kube UpdateAppDBInstance", tracing.ObjectAttributes("AppDBInstance", namespace, name)...)
+		err = config.KubeClient.UpdateAppDBInstance(appdbi)
+		tracing.End(span, err)
+		return err
+	})
+
+	if _, ok := err.(errInstanceUnavailable); ok == false && err != nil {
+		return load, fmt.Errorf("Failed to reserve capacity on AppDBInstance/%s/%s: %v", namespace, name, err)
+	}
+
+	return load, err
+}
+
+// releaseAppDBInstance removes the reservation of the deleted AppDB from the instances of its class.
+// An instance created for the class is marked reclaimable when no other AppDB is placed on it, so that no AppDB is placed on it concurrently.
+// The instance is not deleted, deleting the AppDBInstance would leave the Cloud SQL instance created by its TerraformApply.
+func releaseAppDBInstance(ctx context.Context, parent *appdbv1.AppDB) error {
+	if parent.Spec.InstanceClassName == "" {
+		return nil
+	}
+	logger := logging.FromContext(ctx)
+
+	instances, err := config.KubeClient.ListAppDBInstances(labels.SelectorFromSet(labels.Set{appdbv1.AppDBInstanceClassLabel: parent.Spec.InstanceClassName}))
+	if err != nil {
+		return fmt.Errorf("Failed to list AppDBInstances of class %s: %v", parent.Spec.InstanceClassName, err)
+	}
+
+	appdbs, err := config.KubeClient.ListAppDBs()
+	if err != nil {
+		return fmt.Errorf("Failed to list AppDBs: %v", err)
+	}
+
+	for _, cached := range instances {
+		_, reserved := getReservations(cached)[makeAppDBKey(parent)]
+		if reserved == false && makeAppDBInstanceRef(cached) != parent.Status.AppDBInstance {
+			continue
+		}
+
+		reclaimable := false
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			reclaimable = false
+
+			appdbi, err := config.KubeClient.FetchAppDBInstance(cached.GetNamespace(), cached.GetName())
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			reservations := pruneReservations(getReservations(appdbi), appdbs)
+			delete(reservations, makeAppDBKey(parent))
+			if err := setReservations(&appdbi, reservations); err != nil {
+				return err
+			}
+
+			count, _ := getAppDBInstanceLoad(parent, appdbs, appdbi)
+			if count == 0 && appdbi.Annotations[appdbv1.AppDBInstanceCreatedByClassAnnotation] != "" && isInstanceReclaimable(appdbi) == false {
+				appdbi.Annotations[appdbv1.AppDBInstanceReclaimableAnnotation] = time.Now().UTC().Format(time.RFC3339)
+				reclaimable = true
+			}
+
+			_, span := tracing.Start(ctx, "kube UpdateAppDBInstance", tracing.ObjectAttributes("AppDBInstance", appdbi.GetNamespace(), appdbi.GetName())...)
+			err = config.KubeClient.UpdateAppDBInstance(appdbi)
+			tracing.End(span, err)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to release AppDBInstance/%s: %v", makeAppDBInstanceRef(cached), err)
+		}
+
+		if reclaimable == true {
+			logger.WithChild("AppDBInstance", makeAppDBInstanceRef(cached)).Warnf("Empty AppDBInstance of AppDBInstanceClass/%s marked reclaimable, destroy it with a TerraformDestroy of TerraformApply/appdbi-%s before deleting it", parent.Spec.InstanceClassName, cached.GetName())
+		}
+	}
+
+	return nil
+}
+
+// getReservations returns the reservations of the instance, invalid annotations are ignored.
+func getReservations(appdbi appdbv1.AppDBInstance) map[string]int32 {
+	reservations := make(map[string]int32, 0)
+	if data, ok := appdbi.Annotations[appdbv1.AppDBInstanceReservationsAnnotation]; ok == true {
+		if err := json.Unmarshal([]byte(data), &reservations); err != nil {
+			logging.New().ForObject("AppDBInstance", appdbi.GetNamespace(), appdbi.GetName()).Warnf("Ignoring invalid %s annotation: %v", appdbv1.AppDBInstanceReservationsAnnotation, err)
+			return make(map[string]int32, 0)
+		}
+	}
+	return reservations
+}
+
+func setReservations(appdbi *appdbv1.AppDBInstance, reservations map[string]int32) error {
+	data, err := json.Marshal(reservations)
+	if err != nil {
+		return err
+	}
+	if appdbi.Annotations == nil {
+		appdbi.Annotations = make(map[string]string, 0)
+	}
+	appdbi.Annotations[appdbv1.AppDBInstanceReservationsAnnotation] = string(data)
+	return nil
+}
+
+// pruneReservations removes the reservations of AppDBs that have written their placement to status.appDBInstance, they are counted from the AppDB.
+// Reservations of AppDBs missing from the cache are kept, they may not be in the cache yet, deleted AppDBs remove their reservation in the finalize hook.
+func pruneReservations(reservations map[string]int32, appdbs []appdbv1.AppDB) map[string]int32 {
+	for _, appdb := range appdbs {
+		if appdb.Status.AppDBInstance != "" {
+			delete(reservations, makeAppDBKey(&appdb))
+		}
+	}
+	return reservations
+}
+
+// isInstanceReclaimable returns true if the instance is being deleted or was marked reclaimable by releaseAppDBInstance.
+func isInstanceReclaimable(appdbi appdbv1.AppDBInstance) bool {
+	if appdbi.GetDeletionTimestamp() != nil {
+		return true
+	}
+	_, ok := appdbi.Annotations[appdbv1.AppDBInstanceReclaimableAnnotation]
+	return ok
+}
+
+// getClassInstanceMaxDatabases returns the capacity of an instance of the class, spec.maxDatabases of the instance or the capacity of the class, 0 is unlimited.
+func getClassInstanceMaxDatabases(class appdbv1.AppDBInstanceClass, appdbi appdbv1.AppDBInstance) int32 {
+	if appdbi.Spec.MaxDatabases > 0 {
+		return appdbi.Spec.MaxDatabases
+	}
+	return class.Spec.Placement.MaxDatabasesPerInstance
+}
+
+func makeAppDBKey(appdb *appdbv1.AppDB) string {
+	return fmt.Sprintf("%s/%s", appdb.GetNamespace(), appdb.GetName())
+}
+
+func makeAppDBInstanceRef(appdbi appdbv1.AppDBInstance) string {
+	return fmt.Sprintf("%s/%s", appdbi.GetNamespace(), appdbi.GetName())
+}
+
+// makeAppDBInstanceFromClass returns the new AppDBInstance <class>-<index> for the class, with the capacity for the AppDB that triggered the creation reserved.
+func makeAppDBInstanceFromClass(class appdbv1.AppDBInstanceClass, parent *appdbv1.AppDB, index int) appdbv1.AppDBInstance {
+	namespace := class.Spec.Placement.Namespace
+	if namespace == "" {
+		namespace = parent.GetNamespace()
+	}
+
+	allowedNamespaces := class.Spec.Placement.AllowedNamespaces
+	if allowedNamespaces == nil && namespace != parent.GetNamespace() {
+		allowedNamespaces = &metav1.LabelSelector{}
+	}
+
+	appdbi := appdbv1.AppDBInstance{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "ctl.isla.solutions/v1",
+			Kind:       "AppDBInstance",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", class.GetName(), index),
+			Namespace: namespace,
+			Labels: map[string]string{
+				appdbv1.AppDBInstanceClassLabel: class.GetName(),
+			},
+			Annotations: map[string]string{
+				appdbv1.AppDBInstanceCreatedByClassAnnotation: class.GetName(),
+			},
+		},
+		Spec: appdbv1.AppDBInstanceSpec{
+			Driver:            class.Spec.Driver,
+			AllowedNamespaces: allowedNamespaces,
+			MaxDatabases:      class.Spec.Placement.MaxDatabasesPerInstance,
+		},
+	}
+
+	// The reservation of a new instance cannot fail.
+	setReservations(&appdbi, map[string]int32{makeAppDBKey(parent): parent.Spec.GetSizeHint()})
+
+	return appdbi
+}
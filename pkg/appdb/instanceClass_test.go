@@ -0,0 +1,179 @@
+package appdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newTestAppDB(name string) *appdbv1.AppDB {
+	return &appdbv1.AppDB{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       appdbv1.AppDBSpec{InstanceClassName: "small", DBName: name},
+	}
+}
+
+func setupInstanceClassTest(t *testing.T) *fake.FakeDynamicClient {
+	class := &unstructured.Unstructured{}
+	class.SetAPIVersion("ctl.isla.solutions/v1")
+	class.SetKind("AppDBInstanceClass")
+	class.SetName("small")
+	class.Object["spec"] = map[string]interface{}{"placement": map[string]interface{}{"maxDatabasesPerInstance": int64(2)}}
+
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), class)
+	kubeClient := kubev1.NewClientForInterface(dynamicClient, 0, kubev1.AppDBResource, kubev1.AppDBInstanceResource, kubev1.AppDBInstanceClassResource)
+
+	stopCh := make(chan struct{})
+	if err := kubeClient.Start(stopCh); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	prevConfig := config
+	config = &operator.Config{KubeClient: kubeClient}
+	t.Cleanup(func() {
+		close(stopCh)
+		config = prevConfig
+	})
+
+	return dynamicClient
+}
+
+// waitForCachedInstance waits for the informer cache to have the instance, with the reservation of the AppDB when not nil.
+func waitForCachedInstance(t *testing.T, name string, appdb *appdbv1.AppDB) {
+	for i := 0; i < 100; i++ {
+		if appdbi, err := config.KubeClient.GetAppDBInstance("default", name); err == nil {
+			if appdb == nil {
+				return
+			}
+			if _, ok := getReservations(appdbi)[makeAppDBKey(appdb)]; ok == true {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("AppDBInstance %s not found in cache", name)
+}
+
+func TestSelectAppDBInstanceForClass(t *testing.T) {
+	dynamicClient := setupInstanceClassTest(t)
+	ctx := context.Background()
+
+	a, b, c := newTestAppDB("a"), newTestAppDB("b"), newTestAppDB("c")
+
+	placement, err := selectAppDBInstanceForClass(ctx, a)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if placement.AppDBInstance != "default/small-0" || placement.Created == false {
+		t.Errorf("Expected new instance default/small-0, got: %+v", placement)
+	}
+	waitForCachedInstance(t, "small-0", a)
+
+	placement, err = selectAppDBInstanceForClass(ctx, b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if placement.AppDBInstance != "default/small-0" || placement.Created == true || placement.InstanceLoad != 2 {
+		t.Errorf("Expected existing instance default/small-0 with load 2, got: %+v", placement)
+	}
+
+	// The reservations of a and b fill small-0, even before the cache has the reservation of b.
+	placement, err = selectAppDBInstanceForClass(ctx, c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if placement.AppDBInstance != "default/small-1" || placement.Created == false {
+		t.Errorf("Expected new instance default/small-1, got: %+v", placement)
+	}
+
+	// A second sync of a before its status is written keeps its placement.
+	placement, err = selectAppDBInstanceForClass(ctx, a)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if placement.AppDBInstance != "default/small-0" {
+		t.Errorf("Expected a to stay on default/small-0, got: %+v", placement)
+	}
+
+	waitForCachedInstance(t, "small-0", b)
+
+	// Releasing a keeps small-0, b is still placed on it.
+	a.Status.AppDBInstance = "default/small-0"
+	if err := releaseAppDBInstance(ctx, a); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	obj, err := dynamicClient.Resource(kubev1.AppDBInstanceResource).Namespace("default").Get("small-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected small-0 to be kept, got: %v", err)
+	}
+	if annotation := obj.GetAnnotations()[appdbv1.AppDBInstanceReservationsAnnotation]; annotation != `{"default/b":1}` {
+		t.Errorf("Expected only the reservation of b, got: %s", annotation)
+	}
+
+	// Releasing b marks the empty instance created for the class reclaimable, it is kept until its Cloud SQL instance is destroyed.
+	b.Status.AppDBInstance = "default/small-0"
+	if err := releaseAppDBInstance(ctx, b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	obj, err = dynamicClient.Resource(kubev1.AppDBInstanceResource).Namespace("default").Get("small-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected small-0 to be kept, got: %v", err)
+	}
+	if _, ok := obj.GetAnnotations()[appdbv1.AppDBInstanceReclaimableAnnotation]; ok == false {
+		t.Errorf("Expected small-0 to be marked reclaimable, got annotations: %v", obj.GetAnnotations())
+	}
+
+	// No AppDB is placed on the reclaimable instance.
+	placement, err = selectAppDBInstanceForClass(ctx, newTestAppDB("d"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if placement.AppDBInstance != "default/small-1" {
+		t.Errorf("Expected d on default/small-1, got: %+v", placement)
+	}
+}
+
+func TestReleaseAppDBInstanceKeepsUserInstances(t *testing.T) {
+	dynamicClient := setupInstanceClassTest(t)
+	ctx := context.Background()
+
+	// Instance labeled with the class by the user, not created by the operator.
+	appdbi := appdbv1.AppDBInstance{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "ctl.isla.solutions/v1", Kind: "AppDBInstance"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shared", Labels: map[string]string{appdbv1.AppDBInstanceClassLabel: "small"}},
+	}
+	if err := config.KubeClient.CreateAppDBInstance(appdbi); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	waitForCachedInstance(t, "shared", nil)
+
+	a := newTestAppDB("a")
+	placement, err := selectAppDBInstanceForClass(ctx, a)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if placement.AppDBInstance != "default/shared" {
+		t.Fatalf("Expected instance default/shared, got: %+v", placement)
+	}
+	waitForCachedInstance(t, "shared", a)
+
+	a.Status.AppDBInstance = placement.AppDBInstance
+	if err := releaseAppDBInstance(ctx, a); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	obj, err := dynamicClient.Resource(kubev1.AppDBInstanceResource).Namespace("default").Get("shared", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected instance of the user to be kept, got: %v", err)
+	}
+	if _, ok := obj.GetAnnotations()[appdbv1.AppDBInstanceReclaimableAnnotation]; ok == true {
+		t.Errorf("Expected instance of the user not to be marked reclaimable")
+	}
+}
@@ -0,0 +1,77 @@
+package appdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danisla/appdb-operator/pkg/hook"
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/standalone"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// childResources are the children of the AppDB in the standalone manager mode, same as the childResources of the CompositeController in manifests/appdb-operator.yaml.
+var childResources = []standalone.ChildResource{
+	{APIVersion: "v1", Kind: "Secret", Resource: "secrets", UpdateStrategy: standalone.UpdateStrategyOnDelete},
+	{APIVersion: "batch/v1", Kind: "Job", Resource: "jobs", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "ctl.isla.solutions/v1", Kind: "TerraformApply", Resource: "terraformapplys", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "ctl.isla.solutions/v1", Kind: "TerraformPlan", Resource: "terraformplans", UpdateStrategy: standalone.UpdateStrategyInPlace},
+}
+
+// MakeStandaloneController returns the AppDB controller for the standalone manager mode.
+// AppDBs are also synced when the AppDBInstance they are placed on changes, and finalized to delete their Vault role.
+func MakeStandaloneController() standalone.Controller {
+	return standalone.Controller{
+		Name:           "appdb-operator",
+		ParentResource: kubev1.AppDBResource,
+		ParentType:     &appdbv1.AppDB{},
+		ChildResources: childResources,
+		Watches: []standalone.Watch{
+			{Resource: kubev1.AppDBInstanceResource, Map: appdbsForInstance},
+		},
+		Sync:     Sync,
+		Finalize: Finalize,
+	}
+}
+
+// Sync is the sync hook of the AppDB controller, it converts the request to the typed sync request of the controller.
+func Sync(ctx context.Context, request *hook.SyncRequest) (*hook.SyncResponse, error) {
+	var req SyncRequest
+	if err := request.Decode(&req); err != nil {
+		return nil, fmt.Errorf("Could not convert SyncRequest: %v", err)
+	}
+
+	return hook.SyncParent(ctx, request, eventRecorder, func(ctx context.Context) (interface{}, error) {
+		status, children, err := sync(ctx, ParentDB, &req.Parent, &req.Children)
+		if err != nil || status == nil || children == nil {
+			return nil, err
+		}
+		return &SyncResponse{
+			Status:   *status,
+			Children: *children,
+		}, nil
+	})
+}
+
+// appdbsForInstance returns the AppDBs placed on the AppDBInstance.
+func appdbsForInstance(obj *unstructured.Unstructured) []types.NamespacedName {
+	requests := make([]types.NamespacedName, 0)
+
+	appdbs, err := config.KubeClient.ListAppDBs()
+	if err != nil {
+		logging.New().Errorf("Failed to list AppDBs for AppDBInstance/%s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+		return requests
+	}
+
+	for _, appdb := range appdbs {
+		namespace, name := appdb.GetAppDBInstanceRef()
+		if namespace == obj.GetNamespace() && name == obj.GetName() {
+			requests = append(requests, types.NamespacedName{Namespace: appdb.GetNamespace(), Name: appdb.GetName()})
+		}
+	}
+
+	return requests
+}
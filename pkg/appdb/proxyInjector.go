@@ -0,0 +1,309 @@
+package appdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/danisla/appdb-operator/pkg/cloudsqlproxy"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+const (
+	PROXY_SIDECAR_CONTAINER_NAME = "cloudsql-proxy"
+	PROXY_SIDECAR_VOLUME_NAME    = "cloudsql-proxy-sa-key"
+
+	// NATIVE_SIDECARS_MIN_VERSION is the first Kubernetes version with sidecar init containers enabled by default.
+	NATIVE_SIDECARS_MIN_VERSION = "1.29.0"
+)
+
+// jsonPatchOp is a single RFC 6902 JSON patch operation.
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ProxyInjectorHandler returns the mutating admission webhook that injects the Cloud SQL proxy sidecar into annotated pods.
+// Pods of Jobs need sidecar init containers, which the version of the API server is checked for once.
+func ProxyInjectorHandler() func(w http.ResponseWriter, r *http.Request) {
+	nativeSidecars, err := supportsNativeSidecars()
+	if err != nil {
+		log.Printf("[WARN] Failed to get the Kubernetes version, the Cloud SQL proxy sidecar is not injected into pods of Jobs: %v", err)
+	} else if nativeSidecars == false {
+		log.Printf("[INFO] Kubernetes is older than %s, the Cloud SQL proxy sidecar is not injected into pods of Jobs", NATIVE_SIDECARS_MIN_VERSION)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var review admissionv1beta1.AdmissionReview
+
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "Unsupported method\n")
+			return
+		}
+
+		reqBody, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Printf("[ERROR] Failed to read request body: %v", err)
+			return
+		}
+
+		err = json.Unmarshal(reqBody, &review)
+		if err != nil || review.Request == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Printf("[ERROR] Could not parse AdmissionReview: %v", err)
+			return
+		}
+
+		review.Response = admitPod(review.Request, nativeSidecars)
+		review.Response.UID = review.Request.UID
+
+		data, err := json.Marshal(review)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Printf("[ERROR] Could not generate AdmissionReview response: %v", err)
+			return
+		}
+		w.Write(data)
+	}
+}
+
+// admitPod returns the patch that injects the proxy into the pod.
+// The proxy never exits, so pods of Jobs get it as a sidecar init container, which is stopped when the containers of the pod exit.
+// Without sidecar init containers, pods of Jobs are denied instead of running without a proxy on 127.0.0.1.
+func admitPod(req *admissionv1beta1.AdmissionRequest, nativeSidecars bool) *admissionv1beta1.AdmissionResponse {
+	var pod corev1.Pod
+
+	if err := json.Unmarshal(req.Object.Raw, &pod); err != nil {
+		return denyPod(fmt.Sprintf("Failed to parse pod: %v", err))
+	}
+
+	appdbName, ok := pod.Labels[appdbv1.AppDBNameLabel]
+	if ok == false {
+		// Pod did not opt-in to injection.
+		return &admissionv1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if c.Name == PROXY_SIDECAR_CONTAINER_NAME {
+			// Already injected.
+			return &admissionv1beta1.AdmissionResponse{Allowed: true}
+		}
+	}
+
+	jobName := ""
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "Job" {
+			jobName = ref.Name
+		}
+	}
+
+	appdb, err := config.KubeClient.GetAppDB(req.Namespace, appdbName)
+	if err != nil {
+		return denyPod(fmt.Sprintf("AppDB/%s: Not found", appdbName))
+	}
+
+	appdbiNamespace, appdbiName := appdb.GetAppDBInstanceRef()
+	appdbi, err := config.KubeClient.GetAppDBInstance(appdbiNamespace, appdbiName)
+	if err != nil {
+		return denyPod(fmt.Sprintf("AppDBInstance/%s/%s: Not found", appdbiNamespace, appdbiName))
+	}
+
+	if err := checkAppDBInstanceAllowed(appdbi, req.Namespace); err != nil {
+		return denyPod(err.Error())
+	}
+
+	if appdbi.Spec.Driver.CloudSQLTerraform == nil || appdbi.Spec.Driver.CloudSQLTerraform.Proxy.GetMode() != appdbv1.CloudSQLProxyModeSidecar || appdbi.Spec.Driver.CloudSQLTerraform.Connectivity.GetMode() == appdbv1.CloudSQLConnectivityModePrivateIP {
+		// Instance does not use sidecar proxies.
+		return &admissionv1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	useWorkloadIdentity := appdbi.Spec.Driver.CloudSQLTerraform.Proxy.GetAuth() == appdbv1.CloudSQLProxyAuthWorkloadIdentity
+
+	if useWorkloadIdentity {
+		// The sidecar runs as the service account of the pod, only the service accounts bound to the proxy Google service account can connect.
+		serviceAccount := fmt.Sprintf("%s/%s", req.Namespace, getPodServiceAccountName(pod))
+		if isProxyServiceAccount(appdbi.Spec.Driver.CloudSQLTerraform.Proxy, serviceAccount) == false {
+			return denyPod(fmt.Sprintf("AppDBInstance/%s/%s: ServiceAccount %s of the pod is not in spec.driver.cloudSQLTerraform.proxy.serviceAccounts, it is not bound to the proxy Google service account with Workload Identity", appdbiNamespace, appdbiName, serviceAccount))
+		}
+	}
+
+	if appdbi.Status.CloudSQL == nil || appdbi.Status.CloudSQL.ConnectionName == "" || (useWorkloadIdentity == false && appdbi.Status.CloudSQL.ProxySecret == "") {
+		return denyPod(fmt.Sprintf("AppDBInstance/%s: %s, waiting for Cloud SQL connection info", appdbi.GetName(), appdbi.Status.Provisioning))
+	}
+
+	container, volume := makeCloudSQLProxySidecar(appdbi)
+
+	var patch []jsonPatchOp
+	if jobName == "" {
+		patch = append(patch, jsonPatchOp{
+			Op:    "add",
+			Path:  "/spec/containers/-",
+			Value: container,
+		})
+	} else if nativeSidecars == false {
+		return denyPod(fmt.Sprintf("Pod of Job/%s: the Cloud SQL proxy sidecar would keep the Job from completing, Kubernetes %s or newer is required to inject it into pods of Jobs", jobName, NATIVE_SIDECARS_MIN_VERSION))
+	} else {
+		initContainer, err := makeSidecarInitContainer(container)
+		if err != nil {
+			return denyPod(fmt.Sprintf("Failed to generate sidecar init container: %v", err))
+		}
+		if len(pod.Spec.InitContainers) == 0 {
+			patch = append(patch, jsonPatchOp{
+				Op:    "add",
+				Path:  "/spec/initContainers",
+				Value: []interface{}{initContainer},
+			})
+		} else {
+			// Sidecar init containers run in order, the proxy goes first so the other init containers can use it.
+			patch = append(patch, jsonPatchOp{
+				Op:    "add",
+				Path:  "/spec/initContainers/0",
+				Value: initContainer,
+			})
+		}
+	}
+
+	if useWorkloadIdentity {
+		// The sidecar uses the Kubernetes service account of the pod, no key volume is needed.
+	} else if appdbiNamespace != req.Namespace {
+		return denyPod(fmt.Sprintf("AppDBInstance/%s/%s: proxy auth %s is not supported from other namespaces, the key secret is in namespace %s", appdbiNamespace, appdbiName, appdbv1.CloudSQLProxyAuthServiceAccountKey, appdbiNamespace))
+	} else if len(pod.Spec.Volumes) == 0 {
+		patch = append(patch, jsonPatchOp{
+			Op:    "add",
+			Path:  "/spec/volumes",
+			Value: []corev1.Volume{volume},
+		})
+	} else {
+		patch = append(patch, jsonPatchOp{
+			Op:    "add",
+			Path:  "/spec/volumes/-",
+			Value: volume,
+		})
+	}
+
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return denyPod(fmt.Sprintf("Failed to generate patch: %v", err))
+	}
+
+	logging.New().With(logging.KeyRequestID, string(req.UID)).ForObject("AppDB", appdb.GetNamespace(), appdb.GetName()).Infof("Injecting Cloud SQL proxy sidecar for AppDBInstance/%s/%s into pod", appdbiNamespace, appdbiName)
+
+	patchType := admissionv1beta1.PatchTypeJSONPatch
+
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patchData,
+		PatchType: &patchType,
+	}
+}
+
+func denyPod(msg string) *admissionv1beta1.AdmissionResponse {
+	log.Printf("[WARN] Denying pod for Cloud SQL proxy sidecar injection: %s", msg)
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Message: msg,
+		},
+	}
+}
+
+// makeCloudSQLProxySidecar returns the sidecar container and the service account key volume.
+// With Workload Identity, the volume is not used and the Kubernetes service account of the pod must be in the proxy.serviceAccounts bound to the proxy Google service account.
+func makeCloudSQLProxySidecar(appdbi appdbv1.AppDBInstance) (corev1.Container, corev1.Volume) {
+	proxySpec := appdbi.Spec.Driver.CloudSQLTerraform.Proxy
+
+	volumeMounts := []corev1.VolumeMount{}
+	credentialFile := ""
+
+	if proxySpec.GetAuth() != appdbv1.CloudSQLProxyAuthWorkloadIdentity {
+		credentialFile = fmt.Sprintf("%s/sa-key.json", cloudsqlproxy.CREDENTIALS_DIR)
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      PROXY_SIDECAR_VOLUME_NAME,
+			MountPath: cloudsqlproxy.CREDENTIALS_DIR,
+			ReadOnly:  true,
+		})
+	}
+
+	image, imagePullPolicy := cloudsqlproxy.Image(proxySpec, config.CloudSQLProxyImage, config.CloudSQLProxyImagePullPolicy)
+
+	container := corev1.Container{
+		Name:            PROXY_SIDECAR_CONTAINER_NAME,
+		Image:           image,
+		ImagePullPolicy: imagePullPolicy,
+		Command:         cloudsqlproxy.Command(appdbi.Status.CloudSQL.ConnectionName, "127.0.0.1", appdbi.Status.CloudSQL.Port, appdbi.Spec.Driver.CloudSQLTerraform.Connectivity, credentialFile),
+		VolumeMounts:    volumeMounts,
+		Resources:       cloudsqlproxy.Resources(proxySpec),
+		SecurityContext: cloudsqlproxy.SecurityContext(),
+	}
+
+	volume := corev1.Volume{
+		Name: PROXY_SIDECAR_VOLUME_NAME,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: appdbi.Status.CloudSQL.ProxySecret,
+			},
+		},
+	}
+
+	return container, volume
+}
+
+// makeSidecarInitContainer returns the container as a sidecar init container, with restartPolicy: Always.
+// The vendored core/v1 types predate the field, so it is added to the JSON of the container.
+func makeSidecarInitContainer(container corev1.Container) (map[string]interface{}, error) {
+	var initContainer map[string]interface{}
+	data, err := json.Marshal(container)
+	if err != nil {
+		return initContainer, err
+	}
+	if err := json.Unmarshal(data, &initContainer); err != nil {
+		return initContainer, err
+	}
+	initContainer["restartPolicy"] = "Always"
+	return initContainer, nil
+}
+
+// supportsNativeSidecars returns true if the API server supports sidecar init containers.
+func supportsNativeSidecars() (bool, error) {
+	info, err := config.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return false, err
+	}
+	return serverVersion.AtLeast(version.MustParseGeneric(NATIVE_SIDECARS_MIN_VERSION)), nil
+}
+
+// getPodServiceAccountName returns the service account of the pod, the default service account is used when it is not set.
+func getPodServiceAccountName(pod corev1.Pod) string {
+	if pod.Spec.ServiceAccountName != "" {
+		return pod.Spec.ServiceAccountName
+	}
+	if pod.Spec.DeprecatedServiceAccount != "" {
+		return pod.Spec.DeprecatedServiceAccount
+	}
+	return "default"
+}
+
+// isProxyServiceAccount returns true if the <namespace>/<name> service account is in the service accounts of the proxy spec.
+func isProxyServiceAccount(proxySpec appdbv1.CloudSQLProxySpec, serviceAccount string) bool {
+	for _, sa := range proxySpec.ServiceAccounts {
+		if sa == serviceAccount {
+			return true
+		}
+	}
+	return false
+}
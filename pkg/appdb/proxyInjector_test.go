@@ -0,0 +1,161 @@
+package appdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func setupProxyInjectorTest(t *testing.T) {
+	appdb := &appdbv1.AppDB{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "ctl.isla.solutions/v1", Kind: "AppDB"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db1"},
+		Spec:       appdbv1.AppDBSpec{AppDBInstance: "example", DBName: "db1"},
+	}
+	appdbi := &appdbv1.AppDBInstance{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "ctl.isla.solutions/v1", Kind: "AppDBInstance"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"},
+		Spec: appdbv1.AppDBInstanceSpec{
+			Driver: appdbv1.AppDBDriver{
+				CloudSQLTerraform: &appdbv1.AppDBCloudSQLTerraformDriver{
+					Proxy: appdbv1.CloudSQLProxySpec{Mode: appdbv1.CloudSQLProxyModeSidecar},
+				},
+			},
+		},
+		Status: appdbv1.AppDBInstanceOperatorStatus{
+			Provisioning: appdbv1.ProvisioningStatusComplete,
+			CloudSQL: &appdbv1.AppDBInstanceCloudSQLStatus{
+				ConnectionName: "project:us-central1:example-1234",
+				Port:           3306,
+				ProxySecret:    "example-proxy",
+			},
+		},
+	}
+
+	objs := []runtime.Object{}
+	for _, o := range []interface{}{appdb, appdbi} {
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			t.Fatalf("Failed to convert object: %v", err)
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: data})
+	}
+
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	kubeClient := kubev1.NewClientForInterface(dynamicClient, 0, kubev1.AppDBResource, kubev1.AppDBInstanceResource, kubev1.AppDBInstanceClassResource)
+
+	stopCh := make(chan struct{})
+	if err := kubeClient.Start(stopCh); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	prevConfig := config
+	config = &operator.Config{KubeClient: kubeClient, CloudSQLProxyImage: "gcr.io/cloudsql-docker/gce-proxy:1.16"}
+	t.Cleanup(func() {
+		close(stopCh)
+		config = prevConfig
+	})
+}
+
+func newTestPodRequest(t *testing.T, pod corev1.Pod) *admissionv1beta1.AdmissionRequest {
+	raw, err := json.Marshal(pod)
+	if err != nil {
+		t.Fatalf("Failed to marshal pod: %v", err)
+	}
+	return &admissionv1beta1.AdmissionRequest{Namespace: "default", Object: runtime.RawExtension{Raw: raw}}
+}
+
+func TestAdmitPodJobPods(t *testing.T) {
+	setupProxyInjectorTest(t)
+
+	jobOwner := []metav1.OwnerReference{
+		metav1.OwnerReference{APIVersion: "batch/v1", Kind: "Job", Name: "load"},
+	}
+	labels := map[string]string{appdbv1.AppDBNameLabel: "db1"}
+	initContainers := []corev1.Container{corev1.Container{Name: "migrate"}}
+
+	tests := []struct {
+		name           string
+		pod            corev1.Pod
+		nativeSidecars bool
+		wantAllowed    bool
+		wantPath       string
+		wantInit       bool
+	}{
+		{"deployment pod", corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels}}, false, true, "/spec/containers/-", false},
+		{"job pod", corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels, OwnerReferences: jobOwner}}, true, true, "/spec/initContainers", true},
+		{"job pod with init containers", corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels, OwnerReferences: jobOwner}, Spec: corev1.PodSpec{InitContainers: initContainers}}, true, true, "/spec/initContainers/0", true},
+		{"job pod without sidecar init containers", corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels, OwnerReferences: jobOwner}}, false, false, "", false},
+		{"job pod without label", corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: jobOwner}}, false, true, "", false},
+	}
+
+	for _, tc := range tests {
+		resp := admitPod(newTestPodRequest(t, tc.pod), tc.nativeSidecars)
+		if resp.Allowed != tc.wantAllowed {
+			t.Errorf("%s: expected allowed %v, got: %v: %v", tc.name, tc.wantAllowed, resp.Allowed, resp.Result)
+			continue
+		}
+
+		if tc.wantPath == "" {
+			if resp.Patch != nil {
+				t.Errorf("%s: expected no patch, got: %s", tc.name, string(resp.Patch))
+			}
+			continue
+		}
+
+		var patch []map[string]interface{}
+		if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+			t.Fatalf("%s: failed to parse patch: %v", tc.name, err)
+		}
+		if len(patch) == 0 || patch[0]["path"] != tc.wantPath {
+			t.Errorf("%s: expected proxy patch at %s, got: %s", tc.name, tc.wantPath, string(resp.Patch))
+			continue
+		}
+
+		container, ok := patch[0]["value"].(map[string]interface{})
+		if list, isList := patch[0]["value"].([]interface{}); isList == true && len(list) == 1 {
+			container, ok = list[0].(map[string]interface{})
+		}
+		if ok == false || container["name"] != PROXY_SIDECAR_CONTAINER_NAME {
+			t.Errorf("%s: expected proxy container in patch, got: %s", tc.name, string(resp.Patch))
+			continue
+		}
+		if got := container["restartPolicy"] == "Always"; got != tc.wantInit {
+			t.Errorf("%s: expected sidecar init container %v, got restartPolicy: %v", tc.name, tc.wantInit, container["restartPolicy"])
+		}
+	}
+}
+
+func TestIsProxyServiceAccount(t *testing.T) {
+	proxySpec := appdbv1.CloudSQLProxySpec{ServiceAccounts: []string{"default/app", "team-a/default"}}
+
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want string
+		ok   bool
+	}{
+		{"listed", corev1.Pod{Spec: corev1.PodSpec{ServiceAccountName: "app"}}, "default/app", true},
+		{"not listed", corev1.Pod{Spec: corev1.PodSpec{ServiceAccountName: "other"}}, "default/other", false},
+		{"default service account", corev1.Pod{}, "default/default", false},
+	}
+
+	for _, tc := range tests {
+		serviceAccount := "default/" + getPodServiceAccountName(tc.pod)
+		if serviceAccount != tc.want {
+			t.Errorf("%s: expected service account %s, got: %s", tc.name, tc.want, serviceAccount)
+		}
+		if got := isProxyServiceAccount(proxySpec, serviceAccount); got != tc.ok {
+			t.Errorf("%s: expected %v, got: %v", tc.name, tc.ok, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package appdb is the controller for AppDB parents.
+package appdb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/danisla/appdb-operator/pkg/events"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	"github.com/danisla/appdb-operator/pkg/server"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	vaultv1 "github.com/danisla/appdb-operator/pkg/vault"
+)
+
+var (
+	config         *operator.Config
+	tfDriverConfig *tfdriverv1.TerraformDriverConfig
+	vaultClient    *vaultv1.Client
+	eventRecorder  *events.Recorder
+)
+
+// Setup configures the controller, it must be called before the first sync.
+// The vault client is optional, AppDBs with spec.credentials.mode: vaultDynamic are rejected without it.
+func Setup(c *operator.Config, tfConfig *tfdriverv1.TerraformDriverConfig, vault *vaultv1.Client, recorder *events.Recorder) {
+	config = c
+	tfDriverConfig = tfConfig
+	vaultClient = vault
+	eventRecorder = recorder
+}
+
+// ReadyChecks returns the checks for /readyz: the config of the enabled driver is valid and Vault is reachable with the token of the operator.
+func ReadyChecks() []server.Check {
+	checks := make([]server.Check, 0)
+
+	if tfDriverConfig.Enabled() == true {
+		checks = append(checks, server.Check{
+			Name: "appdb-driver",
+			Func: func() error {
+				if err := tfDriverConfig.Validate(); err != nil {
+					return err
+				}
+				if _, err := os.Stat(DEFAULT_CLOUD_SQL_DB_SOURCE_PATH); err != nil {
+					return fmt.Errorf("Terraform manifest not readable: %v", err)
+				}
+				if tfDriverConfig.IAMUsersImage != "" {
+					if _, err := os.Stat(DEFAULT_CLOUD_SQL_DB_IAM_SOURCE_PATH); err != nil {
+						return fmt.Errorf("Terraform IAM users manifest not readable: %v", err)
+					}
+				}
+				return nil
+			},
+		})
+	}
+
+	if vaultClient != nil {
+		checks = append(checks, server.Check{
+			Name: "vault",
+			// Fails when Vault is unreachable or the token of the operator expired or was revoked.
+			Func: vaultClient.LookupSelf,
+		})
+	}
+
+	return checks
+}
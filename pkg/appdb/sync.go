@@ -1,18 +1,23 @@
-package main
+package appdb
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/syncerr"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	"github.com/danisla/appdb-operator/pkg/tracing"
 	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
-	tfv1 "github.com/danisla/terraform-operator/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jinzhu/copier"
 )
 
-func sync(parentType ParentType, parent *appdbv1.AppDB, children *AppDBChildren) (*appdbv1.AppDBOperatorStatus, *[]interface{}, error) {
+func sync(ctx context.Context, parentType ParentType, parent *appdbv1.AppDB, children *AppDBChildren) (*appdbv1.AppDBOperatorStatus, *[]interface{}, error) {
 	var err error
 	var status appdbv1.AppDBOperatorStatus
 	copier.Copy(&status, &parent.Status)
@@ -24,16 +29,7 @@ func sync(parentType ParentType, parent *appdbv1.AppDB, children *AppDBChildren)
 
 	// Verify required top level fields.
 	if err = verifySpec(parent); err != nil {
-		parent.Log("ERROR", "Invalid spec: %v", err)
-		status.Conditions = append(status.Conditions, appdbv1.AppDBCondition{
-			Type:               appdbv1.ConditionTypeAppDBReady,
-			Status:             appdbv1.ConditionFalse,
-			LastProbeTime:      tNow,
-			LastTransitionTime: tNow,
-			Reason:             "Invalid spec",
-			Message:            fmt.Sprintf("%v", err),
-		})
-		return &status, &desiredChildren, nil
+		return nil, nil, syncerr.InvalidSpec(err)
 	}
 
 	// Map of condition types to conditions, converted to list of conditions after switch statement.
@@ -73,30 +69,37 @@ func sync(parentType ParentType, parent *appdbv1.AppDB, children *AppDBChildren)
 	for _, conditionType := range conditionOrder {
 		condition := conditions[conditionType]
 		newStatus := condition.Status
+		conditionCtx := logging.NewContext(ctx, logging.FromContext(ctx).With(logging.KeyCondition, string(conditionType)))
 
 		// Skip processing conditions with unmet dependencies.
 		if err = checkConditions(conditionType, conditions); err != nil {
 			newStatus = appdbv1.ConditionFalse
 			condition.Reason = err.Error()
 			if condition.Status != newStatus {
+				recordConditionTransition(conditionCtx, parent, condition, newStatus)
 				condition.LastTransitionTime = tNow
 				condition.Status = newStatus
 			}
 			continue
 		}
 
+		conditionCtx, span := tracing.Start(conditionCtx, fmt.Sprintf("reconcile %s", conditionType), attribute.String("appdb.condition", string(conditionType)))
+
 		switch conditionType {
 		case appdbv1.ConditionTypeAppDBInstanceReady:
-			newStatus, appdbi = reconcileAppDBIReady(condition, parent, &status, children, &desiredChildren)
+			newStatus, appdbi = reconcileAppDBIReady(conditionCtx, condition, parent, &status, children, &desiredChildren)
 
 		case appdbv1.ConditionTypeDBCreateComplete:
-			newStatus, tfapply = reconcileDBCreateComplete(condition, parent, &status, children, &desiredChildren, appdbi)
+			newStatus, tfapply = reconcileDBCreateComplete(conditionCtx, condition, parent, &status, children, &desiredChildren, appdbi)
+
+		case appdbv1.ConditionTypeVaultRoleConfigured:
+			newStatus = reconcileVaultRoleConfigured(conditionCtx, condition, parent, &status, children, &desiredChildren, appdbi, tfapply)
 
 		case appdbv1.ConditionTypeCredentialsSecretCreated:
-			newStatus = reconcileSecretCreated(condition, parent, &status, children, &desiredChildren, appdbi, tfapply)
+			newStatus = reconcileSecretCreated(conditionCtx, condition, parent, &status, children, &desiredChildren, appdbi, tfapply)
 
 		case appdbv1.ConditionTypeSnapshotLoadComplete:
-			newStatus = reconcileSnapshotLoadComplete(condition, parent, &status, children, &desiredChildren, appdbi)
+			newStatus = reconcileSnapshotLoadComplete(conditionCtx, condition, parent, &status, children, &desiredChildren, appdbi)
 
 		case appdbv1.ConditionTypeAppDBReady:
 			newStatus = appdbv1.ConditionTrue
@@ -116,10 +119,15 @@ func sync(parentType ParentType, parent *appdbv1.AppDB, children *AppDBChildren)
 			}
 		}
 
+		span.SetAttributes(attribute.String("appdb.condition.status", string(newStatus)), attribute.String("appdb.condition.reason", condition.Reason))
+
 		if condition.Status != newStatus {
+			recordConditionTransition(conditionCtx, parent, condition, newStatus)
 			condition.LastTransitionTime = tNow
 			condition.Status = newStatus
 		}
+
+		span.End()
 	}
 
 	// Copy updated conditions back to status in order.
@@ -1,8 +1,8 @@
-package main
+package appdb
 
 import (
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
 	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
-	tfv1 "github.com/danisla/terraform-operator/pkg/types"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -33,10 +33,11 @@ type AppDBChildren struct {
 	Jobs            map[string]batchv1.Job    `json:"Job.batch/v1"`
 }
 
-// Order of condition status
-var conditionStatusOrder = []appdbv1.AppDBConditionType{
+// ConditionStatusOrder is the order of the conditions in the status, also used by the AppDB metrics collector.
+var ConditionStatusOrder = []appdbv1.AppDBConditionType{
 	appdbv1.ConditionTypeAppDBInstanceReady,
 	appdbv1.ConditionTypeDBCreateComplete,
+	appdbv1.ConditionTypeVaultRoleConfigured,
 	appdbv1.ConditionTypeCredentialsSecretCreated,
 	appdbv1.ConditionTypeSnapshotLoadComplete,
 	appdbv1.ConditionTypeAppDBReady,
@@ -46,8 +47,12 @@ var conditionDependencies = map[appdbv1.AppDBConditionType][]appdbv1.AppDBCondit
 	appdbv1.ConditionTypeDBCreateComplete: []appdbv1.AppDBConditionType{
 		appdbv1.ConditionTypeAppDBInstanceReady,
 	},
+	appdbv1.ConditionTypeVaultRoleConfigured: []appdbv1.AppDBConditionType{
+		appdbv1.ConditionTypeDBCreateComplete,
+	},
 	appdbv1.ConditionTypeCredentialsSecretCreated: []appdbv1.AppDBConditionType{
 		appdbv1.ConditionTypeDBCreateComplete,
+		appdbv1.ConditionTypeVaultRoleConfigured,
 	},
 	appdbv1.ConditionTypeSnapshotLoadComplete: []appdbv1.AppDBConditionType{
 		appdbv1.ConditionTypeCredentialsSecretCreated,
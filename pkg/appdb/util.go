@@ -0,0 +1,120 @@
+package appdb
+
+import (
+	"fmt"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// checkAppDBInstanceAllowed returns an error if AppDBs in the namespace are not allowed to use the AppDBInstance.
+func checkAppDBInstanceAllowed(appdbi appdbv1.AppDBInstance, namespace string) error {
+	if namespace == appdbi.GetNamespace() {
+		return nil
+	}
+
+	if appdbi.Spec.AllowedNamespaces == nil {
+		return fmt.Errorf("AppDBInstance/%s/%s does not allow references from other namespaces", appdbi.GetNamespace(), appdbi.GetName())
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(appdbi.Spec.AllowedNamespaces)
+	if err != nil {
+		return fmt.Errorf("Invalid spec.allowedNamespaces of AppDBInstance/%s/%s: %v", appdbi.GetNamespace(), appdbi.GetName(), err)
+	}
+
+	ns, err := config.Clientset.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Failed to get namespace %s: %v", namespace, err)
+	}
+
+	if selector.Matches(labels.Set(ns.GetLabels())) == false {
+		return fmt.Errorf("Namespace %s is not in spec.allowedNamespaces of AppDBInstance/%s/%s", namespace, appdbi.GetNamespace(), appdbi.GetName())
+	}
+
+	return nil
+}
+
+func makeCredentialsSecret(name, namespace, user, password, dbname, dbhost string, dbport int32) corev1.Secret {
+	var secret corev1.Secret
+
+	data := make(map[string]string, 0)
+
+	data["dbname"] = dbname
+	data["dbhost"] = dbhost
+	data["dbport"] = fmt.Sprintf("%d", dbport)
+	data["user"] = user
+	data["password"] = password
+
+	secret = corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		StringData: data,
+	}
+
+	return secret
+}
+
+func makeIAMCredentialsSecret(name, namespace, user, serviceAccount, dbname, dbhost string, dbport int32) corev1.Secret {
+	var secret corev1.Secret
+
+	data := make(map[string]string, 0)
+
+	data["dbname"] = dbname
+	data["dbhost"] = dbhost
+	data["dbport"] = fmt.Sprintf("%d", dbport)
+	data["user"] = user
+	data["serviceAccount"] = serviceAccount
+
+	secret = corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		StringData: data,
+	}
+
+	return secret
+}
+
+func makeVaultCredentialsSecret(name, namespace, rolePath, dbname, dbhost string, dbport int32) corev1.Secret {
+	var secret corev1.Secret
+
+	data := make(map[string]string, 0)
+
+	data["dbname"] = dbname
+	data["dbhost"] = dbhost
+	data["dbport"] = fmt.Sprintf("%d", dbport)
+	data["vaultRolePath"] = rolePath
+
+	secret = corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		StringData: data,
+	}
+
+	return secret
+}
+
+func addSecretData(secret *corev1.Secret, data map[string]string) {
+	for k, v := range data {
+		secret.StringData[k] = v
+	}
+}
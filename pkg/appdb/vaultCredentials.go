@@ -0,0 +1,133 @@
+package appdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danisla/appdb-operator/pkg/operator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	vaultv1 "github.com/danisla/appdb-operator/pkg/vault"
+)
+
+// DatabaseEngine represents the database engine of an AppDBInstance.
+type DatabaseEngine string
+
+const (
+	DatabaseEngineMySQL    DatabaseEngine = "mysql"
+	DatabaseEnginePostgres DatabaseEngine = "postgres"
+)
+
+// getDatabaseEngine returns the database engine from the driver params of the AppDBInstance.
+func getDatabaseEngine(appdbi appdbv1.AppDBInstance) (DatabaseEngine, string, error) {
+	if appdbi.Spec.Driver.CloudSQLTerraform == nil {
+		return "", "", fmt.Errorf("Unsupported AppDBInstance driver")
+	}
+
+	dbVersion := appdbi.Spec.Driver.CloudSQLTerraform.Params["database_version"]
+	switch {
+	case strings.HasPrefix(dbVersion, "MYSQL"):
+		return DatabaseEngineMySQL, dbVersion, nil
+	case strings.HasPrefix(dbVersion, "POSTGRES"):
+		return DatabaseEnginePostgres, dbVersion, nil
+	}
+
+	return "", dbVersion, fmt.Errorf("Unsupported database_version: %s", dbVersion)
+}
+
+// makeVaultAdminUser returns the name of the database user created for Vault, unique per AppDB and at most 16 characters for MySQL 5.6.
+func makeVaultAdminUser(parent *appdbv1.AppDB) string {
+	return fmt.Sprintf("vault-%s", operator.CalcParentSig(fmt.Sprintf("%s/%s", parent.GetNamespace(), parent.GetName()), "")[:10])
+}
+
+func makeVaultConnectionName(parent *appdbv1.AppDB, appdbi appdbv1.AppDBInstance) string {
+	return fmt.Sprintf("appdb-%s-%s-%s", parent.GetNamespace(), appdbi.GetName(), parent.GetName())
+}
+
+// makeVaultRoleName returns the name of the Vault role of the AppDB.
+// spec.credentials.vault.roleName is prefixed with the namespace and suffixed with the UID of the AppDB, the mount is shared by all namespaces
+// and Vault roles are written without a version check, so AppDBs with the same roleName must not write the same role.
+// The default name is unique per AppDB.
+func makeVaultRoleName(parent *appdbv1.AppDB, appdbi appdbv1.AppDBInstance) string {
+	if parent.Spec.Credentials.Vault != nil && parent.Spec.Credentials.Vault.RoleName != "" {
+		return fmt.Sprintf("%s-%s-%s", parent.GetNamespace(), parent.Spec.Credentials.Vault.RoleName, parent.GetUID())
+	}
+	return makeVaultConnectionName(parent, appdbi)
+}
+
+func makeVaultDatabaseConnection(roleName, user, password, dbname string, appdbi appdbv1.AppDBInstance) (vaultv1.DatabaseConnection, error) {
+	var conn vaultv1.DatabaseConnection
+
+	engine, dbVersion, err := getDatabaseEngine(appdbi)
+	if err != nil {
+		return conn, err
+	}
+
+	if appdbi.Spec.Driver.CloudSQLTerraform.Proxy.GetMode() == appdbv1.CloudSQLProxyModeSidecar {
+		return conn, fmt.Errorf("Vault cannot reach the database through a sidecar proxy, AppDBInstance/%s must use proxy mode: %s", appdbi.GetName(), appdbv1.CloudSQLProxyModeDeployment)
+	}
+
+	conn = vaultv1.DatabaseConnection{
+		Username:     user,
+		Password:     password,
+		AllowedRoles: []string{roleName},
+	}
+
+	// The Cloud SQL proxy encrypts the connection to the instance and does not serve TLS itself.
+	// Without the proxy Vault connects to the private IP of the instance and must use TLS, the server CA of the instance is not available to Vault, so the certificate is not verified.
+	direct := appdbi.Spec.Driver.CloudSQLTerraform.Connectivity.GetMode() == appdbv1.CloudSQLConnectivityModePrivateIP
+
+	switch engine {
+	case DatabaseEngineMySQL:
+		// MySQL 5.6 limits user names to 16 characters, which requires the legacy plugin.
+		conn.PluginName = "mysql-database-plugin"
+		if strings.HasPrefix(dbVersion, "MYSQL_5_6") {
+			conn.PluginName = "mysql-legacy-database-plugin"
+		}
+		conn.ConnectionURL = fmt.Sprintf("{{username}}:{{password}}@tcp(%s:%d)/", appdbi.Status.DBHost, appdbi.Status.DBPort)
+		if direct == true {
+			conn.ConnectionURL += "?tls=skip-verify"
+		}
+	case DatabaseEnginePostgres:
+		sslMode := "disable"
+		if direct == true {
+			sslMode = "require"
+		}
+		conn.PluginName = "postgresql-database-plugin"
+		conn.ConnectionURL = fmt.Sprintf("postgresql://{{username}}:{{password}}@%s:%d/%s?sslmode=%s", appdbi.Status.DBHost, appdbi.Status.DBPort, dbname, sslMode)
+	}
+
+	return conn, nil
+}
+
+func makeVaultDatabaseRole(connectionName, dbname string, spec *appdbv1.AppDBVaultCredentialsSpec, appdbi appdbv1.AppDBInstance) (vaultv1.DatabaseRole, error) {
+	var role vaultv1.DatabaseRole
+
+	engine, _, err := getDatabaseEngine(appdbi)
+	if err != nil {
+		return role, err
+	}
+
+	role = vaultv1.DatabaseRole{
+		DBName: connectionName,
+	}
+
+	if spec != nil {
+		role.DefaultTTL = spec.DefaultTTL
+		role.MaxTTL = spec.MaxTTL
+	}
+
+	switch engine {
+	case DatabaseEngineMySQL:
+		role.CreationStatements = []string{
+			"CREATE USER '{{name}}'@'%' IDENTIFIED BY '{{password}}';",
+			fmt.Sprintf("GRANT ALL PRIVILEGES ON `%s`.* TO '{{name}}'@'%%';", dbname),
+		}
+	case DatabaseEnginePostgres:
+		role.CreationStatements = []string{
+			"CREATE ROLE \"{{name}}\" WITH LOGIN PASSWORD '{{password}}' VALID UNTIL '{{expiration}}';",
+			fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE \"%s\" TO \"{{name}}\";", dbname),
+		}
+	}
+
+	return role, nil
+}
@@ -0,0 +1,69 @@
+package appdb
+
+import (
+	"fmt"
+	"strings"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+)
+
+func verifySpec(parent *appdbv1.AppDB) error {
+	if parent.Spec.AppDBInstance == "" && parent.Spec.InstanceClassName == "" {
+		return fmt.Errorf("Missing spec.appDBInstance or spec.instanceClassName")
+	}
+
+	if parent.Spec.AppDBInstance != "" && parent.Spec.InstanceClassName != "" {
+		return fmt.Errorf("Only one of spec.appDBInstance or spec.instanceClassName can be set")
+	}
+
+	if parts := strings.Split(parent.Spec.AppDBInstance, "/"); len(parts) > 2 || (len(parts) == 2 && (parts[0] == "" || parts[1] == "")) {
+		return fmt.Errorf("Invalid spec.appDBInstance: %s, must be <name> or <namespace>/<name>", parent.Spec.AppDBInstance)
+	}
+
+	if parent.Spec.DBName == "" {
+		return fmt.Errorf("Missing spec.dbName")
+	}
+
+	if len(parent.Spec.Users) == 0 && len(parent.Spec.IAMUsers) == 0 {
+		return fmt.Errorf("spec.users and spec.iamUsers lists are empty, must have at least 1 user")
+	}
+
+	for i, u := range parent.Spec.IAMUsers {
+		if u.Type != appdbv1.AppDBUserTypeIAMServiceAccount {
+			return fmt.Errorf("Unsupported spec.iamUsers[%d].type: %s, must be: %s", i, u.Type, appdbv1.AppDBUserTypeIAMServiceAccount)
+		}
+		if strings.HasSuffix(u.ServiceAccount, ".gserviceaccount.com") == false {
+			return fmt.Errorf("spec.iamUsers[%d].serviceAccount must be a Google service account email", i)
+		}
+	}
+
+	if len(parent.Spec.IAMUsers) > 0 && tfDriverConfig.IAMUsersImage == "" {
+		// The default TF_IMAGE runs Terraform 0.11, which cannot apply the IAM users config.
+		return fmt.Errorf("spec.iamUsers requires a Terraform 0.12 or newer image, the operator was started without TF_IAM_USERS_IMAGE")
+	}
+
+	if parent.Spec.LoadURL != "" && len(parent.Spec.Users) == 0 {
+		return fmt.Errorf("spec.loadURL requires at least 1 user in spec.users")
+	}
+
+	if parent.Spec.LoadURL != "" {
+		if _, err := makeLoadURL(parent.Spec.LoadURL); err != nil {
+			return err
+		}
+	}
+
+	switch parent.Spec.GetCredentialsMode() {
+	case appdbv1.CredentialsModeStatic:
+	case appdbv1.CredentialsModeVaultDynamic:
+		if len(parent.Spec.IAMUsers) > 0 {
+			return fmt.Errorf("spec.iamUsers is not supported with spec.credentials.mode %s", appdbv1.CredentialsModeVaultDynamic)
+		}
+		if vaultClient == nil {
+			return fmt.Errorf("spec.credentials.mode is %s but the operator was started without VAULT_ADDR", appdbv1.CredentialsModeVaultDynamic)
+		}
+	default:
+		return fmt.Errorf("Unsupported spec.credentials.mode: %s, must be one of: %s, %s", parent.Spec.Credentials.Mode, appdbv1.CredentialsModeStatic, appdbv1.CredentialsModeVaultDynamic)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package appdb
+
+import (
+	"testing"
+
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+)
+
+func TestVerifySpecIAMUsersImage(t *testing.T) {
+	defer func(c *tfdriverv1.TerraformDriverConfig) { tfDriverConfig = c }(tfDriverConfig)
+
+	iamUser := appdbv1.AppDBIAMUser{Type: appdbv1.AppDBUserTypeIAMServiceAccount, ServiceAccount: "app@project.iam.gserviceaccount.com"}
+
+	tests := []struct {
+		name          string
+		iamUsers      []appdbv1.AppDBIAMUser
+		iamUsersImage string
+		wantErr       bool
+	}{
+		{"no IAM users without image", nil, "", false},
+		{"IAM users with image", []appdbv1.AppDBIAMUser{iamUser}, "gcr.io/project/terraform-pod:v0.12.31", false},
+		{"IAM users without image", []appdbv1.AppDBIAMUser{iamUser}, "", true},
+	}
+
+	for _, tc := range tests {
+		tfDriverConfig = &tfdriverv1.TerraformDriverConfig{Image: "gcr.io/cloud-solutions-group/terraform-pod:v0.11.8", IAMUsersImage: tc.iamUsersImage}
+		parent := newTestAppDB("example")
+		parent.Spec.Users = []string{"app"}
+		parent.Spec.IAMUsers = tc.iamUsers
+		err := verifySpec(parent)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error %v, got: %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
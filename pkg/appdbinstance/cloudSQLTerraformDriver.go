@@ -0,0 +1,548 @@
+package appdbinstance
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danisla/appdb-operator/pkg/cloudsqlproxy"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	DEFAULT_CLOUD_SQL_SOURCE_PATH = "/config/dbinstance/main.tf"
+	DEFAULT_CLOUD_SQL_DISK_TYPE   = "PD_SSD"
+)
+
+// cloudSQLSourcePath is the path of the Terraform manifest of the instance, tests point it at a local file.
+var cloudSQLSourcePath = DEFAULT_CLOUD_SQL_SOURCE_PATH
+
+// makeTFSig returns the signature of the Terraform inputs of the instance, the spec and the IAM authentication flag set from the AppDBs.
+// The flag is only added when set so that the signature of existing instances does not change.
+func makeTFSig(parent *appdbv1.AppDBInstance, iamAuth bool) string {
+	addStr := ""
+	if iamAuth == true {
+		addStr = "iam_authentication"
+	}
+	return operator.CalcParentSig(parent.Spec, addStr)
+}
+
+func makeCloudSQLTerraform(tfApplyName string, parent *appdbv1.AppDBInstance, iamAuth bool) (tfv1.Terraform, error) {
+	var tfapply tfv1.Terraform
+
+	manifest, err := tfdriverv1.LoadManifest(cloudSQLSourcePath)
+	if err != nil {
+		return tfapply, fmt.Errorf("Error loading cloud sql terraform manifest from %s: %v", cloudSQLSourcePath, err)
+	}
+
+	tfvars, err := makeTFVars(tfApplyName, parent)
+	if err != nil {
+		return tfapply, fmt.Errorf("Failed to generate tfvars from driver config: %v", err)
+	}
+
+	if iamAuth == true {
+		// Required by the IAM users of the AppDBs, overrides the iam_authentication param.
+		tfvars["iam_authentication"] = "true"
+	}
+
+	parentSig := makeTFSig(parent, iamAuth)
+
+	tfapply = tfv1.Terraform{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "ctl.isla.solutions/v1",
+			Kind:       "TerraformApply",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tfApplyName,
+			Namespace: parent.GetNamespace(),
+			Annotations: map[string]string{
+				"appdb-parent-sig": parentSig,
+			},
+		},
+		Spec: tfv1.TerraformSpec{
+			Image:           tfDriverConfig.Image,
+			ImagePullPolicy: tfDriverConfig.ImagePullPolicy,
+			BackendBucket:   tfDriverConfig.BackendBucket,
+			BackendPrefix:   tfDriverConfig.BackendPrefix,
+			ProviderConfig: map[string]tfv1.TerraformSpecProviderConfig{
+				"google": tfv1.TerraformSpecProviderConfig{
+					SecretName: tfDriverConfig.GoogleProviderConfigSecret,
+				},
+			},
+			Sources: []tfv1.TerraformConfigSource{
+				tfv1.TerraformConfigSource{
+					Embedded: manifest,
+				},
+			},
+			TFVars: tfvars,
+		},
+	}
+
+	return tfapply, nil
+}
+
+func makeTFVars(name string, parent *appdbv1.AppDBInstance) (map[string]string, error) {
+	cfg := parent.Spec.Driver.CloudSQLTerraform
+
+	var tfvars = make(map[string]string, 0)
+
+	// Names must be unique and cannot be reused across destroys.
+	// the Terraform source will create a new name using this as a prefix.
+	tfvars["name"] = name
+
+	// Marshal params to json and unmarshal as tfvars
+	data, err := json.Marshal(cfg.Params)
+	if err != nil {
+		return tfvars, err
+	}
+
+	var paramsJSON map[string]string
+	err = json.Unmarshal(data, &paramsJSON)
+	if err != nil {
+		return tfvars, err
+	}
+
+	for k, v := range paramsJSON {
+		tfvars[k] = v
+	}
+
+	switch cfg.Connectivity.GetMode() {
+	case appdbv1.CloudSQLConnectivityModePrivateIP, appdbv1.CloudSQLConnectivityModePrivateIPProxy:
+		// Instance is created on the VPC network without a public IP.
+		if cfg.Connectivity.Network == "" {
+			return tfvars, fmt.Errorf("connectivity.network is required for connectivity mode: %s", cfg.Connectivity.GetMode())
+		}
+		tfvars["private_network"] = cfg.Connectivity.Network
+	}
+
+	if cfg.Connectivity.TLS == true {
+		tfvars["require_ssl"] = "true"
+	}
+
+	if cfg.Proxy.GetAuth() == appdbv1.CloudSQLProxyAuthWorkloadIdentity {
+		// Binds the Kubernetes service accounts the proxy runs as to the proxy Google service account instead of creating a key.
+		tfvars["proxy_auth"] = string(appdbv1.CloudSQLProxyAuthWorkloadIdentity)
+		if cfg.Proxy.GetMode() == appdbv1.CloudSQLProxyModeSidecar {
+			// The sidecar runs as the service account of the app pod.
+			tfvars["k8s_service_accounts"] = strings.Join(cfg.Proxy.ServiceAccounts, ",")
+		} else {
+			tfvars["k8s_service_accounts"] = fmt.Sprintf("%s/%s", parent.GetNamespace(), makeCloudSQLProxyName(parent))
+		}
+	}
+
+	return tfvars, nil
+}
+
+// verifyProxySpec returns an error if the proxy spec cannot be used, the sidecar with workloadIdentity auth needs the service accounts of the app pods.
+func verifyProxySpec(proxySpec appdbv1.CloudSQLProxySpec) error {
+	if proxySpec.Replicas < 0 {
+		// An explicit 0 is rejected by the CRD schema, it is the unset value here.
+		return fmt.Errorf("Invalid spec.driver.cloudSQLTerraform.proxy.replicas: %d, must be at least 1", proxySpec.Replicas)
+	}
+
+	if proxySpec.GetMode() == appdbv1.CloudSQLProxyModeSidecar && proxySpec.GetAuth() == appdbv1.CloudSQLProxyAuthWorkloadIdentity && len(proxySpec.ServiceAccounts) == 0 {
+		return fmt.Errorf("spec.driver.cloudSQLTerraform.proxy.serviceAccounts is required with proxy mode %s and auth %s, the sidecar runs as the service account of the app pod", appdbv1.CloudSQLProxyModeSidecar, appdbv1.CloudSQLProxyAuthWorkloadIdentity)
+	}
+
+	for i, sa := range proxySpec.ServiceAccounts {
+		if parts := strings.Split(sa, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("Invalid spec.driver.cloudSQLTerraform.proxy.serviceAccounts[%d]: %s, must be <namespace>/<name>", i, sa)
+		}
+	}
+
+	return nil
+}
+
+// CloudSQLProxyChildren are the child resources that make up the Cloud SQL Proxy.
+// Secret is nil when using Workload Identity, ServiceAccount is nil when using a service account key.
+type CloudSQLProxyChildren struct {
+	Secret              *corev1.Secret
+	ServiceAccount      *corev1.ServiceAccount
+	Deployment          appsv1beta1.Deployment
+	Service             corev1.Service
+	PodDisruptionBudget policyv1beta1.PodDisruptionBudget
+}
+
+func makeCloudSQLProxyName(parent *appdbv1.AppDBInstance) string {
+	return fmt.Sprintf("%s-proxy", parent.Name)
+}
+
+func makeCloudSQLProxy(parent *appdbv1.AppDBInstance, tfapply tfv1.Terraform) (CloudSQLProxyChildren, error) {
+	var proxy CloudSQLProxyChildren
+
+	name := makeCloudSQLProxyName(parent)
+
+	namespace := parent.GetNamespace()
+
+	selector := map[string]string{"app": name}
+
+	proxySpec := parent.Spec.Driver.CloudSQLTerraform.Proxy
+
+	replicas := proxySpec.GetReplicas()
+
+	image, imagePullPolicy := cloudsqlproxy.Image(proxySpec, config.CloudSQLProxyImage, config.CloudSQLProxyImagePullPolicy)
+
+	affinity := proxySpec.Affinity
+	if affinity == nil {
+		affinity = makeCloudSQLProxyDefaultAffinity(selector)
+	}
+
+	credentialFile := ""
+	podAnnotations := map[string]string{}
+	serviceAccountName := ""
+	volumeMounts := []corev1.VolumeMount{}
+	volumes := []corev1.Volume{}
+
+	if proxySpec.GetAuth() == appdbv1.CloudSQLProxyAuthWorkloadIdentity && proxySpec.GetMode() == appdbv1.CloudSQLProxyModeSidecar {
+		// The sidecar runs as the service account of the app pod, no proxy service account is created.
+	} else if proxySpec.GetAuth() == appdbv1.CloudSQLProxyAuthWorkloadIdentity {
+		// Proxy pods run as a Kubernetes service account bound to the proxy Google service account.
+		saEmailOutput, ok := tfapply.Status.TFOutput["proxy_sa_email"]
+		if ok == false {
+			return proxy, fmt.Errorf("Missing 'proxy_sa_email' in TerraformApply output")
+		}
+
+		proxy.ServiceAccount = &corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ServiceAccount",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					"iam.gke.io/gcp-service-account": saEmailOutput.Value,
+				},
+			},
+		}
+
+		serviceAccountName = name
+	} else {
+		credentialFile = fmt.Sprintf("%s/sa-key.json", cloudsqlproxy.CREDENTIALS_DIR)
+
+		// Extract service account key from TerraformApply output variable base64 encoded value.
+		saKeyOutput, ok := tfapply.Status.TFOutput["proxy_sa_key"]
+		if ok == false {
+			return proxy, fmt.Errorf("Missing 'proxy_sa_key' in TerraformApply output")
+		}
+
+		saKey, err := base64.StdEncoding.DecodeString(saKeyOutput.Value)
+		if err != nil {
+			return proxy, fmt.Errorf("Failed to decode 'proxy_sa_key' value from TerraformApply output var: %v", err)
+		}
+
+		proxy.Secret = &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				"sa-key.json": string(saKey),
+			},
+		}
+
+		// Changes to the secret roll the proxy pods.
+		podAnnotations["appdb-proxy-secret-sig"] = operator.CalcParentSig(proxy.Secret.StringData, "")
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "sa-key",
+			MountPath: cloudsqlproxy.CREDENTIALS_DIR,
+		})
+
+		volumes = append(volumes, corev1.Volume{
+			Name: "sa-key",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: name,
+				},
+			},
+		})
+	}
+
+	proxy.Deployment = appsv1beta1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1beta1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1beta1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      selector,
+					Annotations: podAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					Containers: []corev1.Container{
+						corev1.Container{
+							Name:            "cloudsql-proxy",
+							Image:           image,
+							ImagePullPolicy: imagePullPolicy,
+							Command:         cloudsqlproxy.Command(parent.Status.CloudSQL.ConnectionName, "0.0.0.0", parent.Status.CloudSQL.Port, parent.Spec.Driver.CloudSQLTerraform.Connectivity, credentialFile),
+							VolumeMounts:    volumeMounts,
+							Ports: []corev1.ContainerPort{
+								corev1.ContainerPort{
+									Name:          "sql",
+									ContainerPort: parent.Status.CloudSQL.Port,
+								},
+							},
+							Resources: cloudsqlproxy.Resources(proxySpec),
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									TCPSocket: &corev1.TCPSocketAction{
+										Port: intstr.FromString("sql"),
+									},
+								},
+								InitialDelaySeconds: 5,
+								PeriodSeconds:       10,
+							},
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									TCPSocket: &corev1.TCPSocketAction{
+										Port: intstr.FromString("sql"),
+									},
+								},
+								InitialDelaySeconds: 15,
+								PeriodSeconds:       20,
+								FailureThreshold:    6,
+							},
+							SecurityContext: cloudsqlproxy.SecurityContext(),
+						},
+					}, // Containers
+					NodeSelector: proxySpec.NodeSelector,
+					Tolerations:  proxySpec.Tolerations,
+					Affinity:     affinity,
+					Volumes:      volumes,
+				}, // PodSpec
+			}, // PodTemplateSpec
+		}, // DeploymentSpec
+	} // Deployment
+
+	proxy.Deployment.Annotations = map[string]string{
+		"appdb-proxy-sig": operator.CalcParentSig(proxy.Deployment.Spec, ""),
+	}
+
+	proxy.Service = corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				corev1.ServicePort{
+					Name: "sql",
+					Port: parent.Status.CloudSQL.Port,
+					TargetPort: intstr.IntOrString{
+						Type:   intstr.String,
+						StrVal: "sql",
+					},
+				},
+			},
+			Selector: selector,
+		},
+	}
+
+	// PodDisruptionBudget is only used when there is more than 1 replica, otherwise it would block node drains.
+	minAvailable := intstr.FromInt(1)
+	proxy.PodDisruptionBudget = policyv1beta1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1beta1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		},
+	}
+
+	return proxy, nil
+}
+
+// makeCloudSQLProxyNetworkPolicy allows ingress to the proxy pods only from pods labeled with the name of an AppDB bound to the instance, plus the proxy.allowedFrom peers.
+// Pods of AppDBs in other namespaces are selected within the spec.allowedNamespaces of the instance.
+// The policy only restricts pods that cannot set the label: anyone who can create pods in an allowed namespace can label them with the name
+// of a bound AppDB and reach the proxy, so the namespaces, not the label, are the trust boundary.
+func makeCloudSQLProxyNetworkPolicy(parent *appdbv1.AppDBInstance, appdbs []appdbv1.AppDB) networkingv1.NetworkPolicy {
+	name := makeCloudSQLProxyName(parent)
+
+	localNames := []string{}
+	remoteNames := []string{}
+	for _, appdb := range appdbs {
+		if appdb.GetNamespace() == parent.GetNamespace() {
+			localNames = append(localNames, appdb.GetName())
+		} else if parent.Spec.AllowedNamespaces != nil {
+			remoteNames = append(remoteNames, appdb.GetName())
+		}
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{}
+
+	if len(localNames) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			PodSelector: makeAppDBNamePodSelector(localNames),
+		})
+	}
+
+	if len(remoteNames) > 0 {
+		// Any AppDB name matches in every allowed namespace, the label is set by the pod author and is not verified.
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: parent.Spec.AllowedNamespaces,
+			PodSelector:       makeAppDBNamePodSelector(remoteNames),
+		})
+	}
+
+	peers = append(peers, parent.Spec.Driver.CloudSQLTerraform.Proxy.AllowedFrom...)
+
+	// An ingress rule without peers allows all sources, so no rule is added when there are no peers, which denies all ingress.
+	ingress := []networkingv1.NetworkPolicyIngressRule{}
+	if len(peers) > 0 {
+		protocol := corev1.ProtocolTCP
+		port := intstr.FromString("sql")
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: peers,
+			Ports: []networkingv1.NetworkPolicyPort{
+				networkingv1.NetworkPolicyPort{
+					Protocol: &protocol,
+					Port:     &port,
+				},
+			},
+		})
+	}
+
+	return networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: parent.GetNamespace(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": name},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+}
+
+func makeAppDBNamePodSelector(appdbNames []string) *metav1.LabelSelector {
+	values := []string{}
+	seen := make(map[string]bool, 0)
+	for _, n := range appdbNames {
+		if seen[n] == false {
+			values = append(values, n)
+			seen[n] = true
+		}
+	}
+	sort.Strings(values)
+
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			metav1.LabelSelectorRequirement{
+				Key:      appdbv1.AppDBNameLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   values,
+			},
+		},
+	}
+}
+
+// makeCloudSQLProxyDefaultAffinity spreads the proxy pods across zones and nodes.
+func makeCloudSQLProxyDefaultAffinity(selector map[string]string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				corev1.WeightedPodAffinityTerm{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: selector,
+						},
+						TopologyKey: "failure-domain.beta.kubernetes.io/zone",
+					},
+				},
+				corev1.WeightedPodAffinityTerm{
+					Weight: 50,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: selector,
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+}
+
+func makeCloudSQLProxyRolloutStatus(deploy appsv1beta1.Deployment, currDeploy appsv1beta1.Deployment, found bool) *appdbv1.CloudSQLProxyRolloutStatus {
+	rollout := appdbv1.CloudSQLProxyRolloutStatus{
+		State: appdbv1.ProvisioningStatusPending,
+		Sig:   deploy.Annotations["appdb-proxy-sig"],
+	}
+
+	if found == false {
+		// Deployment is being created.
+		return &rollout
+	}
+
+	rollout.ObservedGeneration = currDeploy.Status.ObservedGeneration
+	rollout.Replicas = currDeploy.Status.Replicas
+	rollout.UpdatedReplicas = currDeploy.Status.UpdatedReplicas
+	rollout.AvailableReplicas = currDeploy.Status.AvailableReplicas
+
+	if currDeploy.Annotations["appdb-proxy-sig"] != rollout.Sig {
+		// Update is being applied.
+		return &rollout
+	}
+
+	var desiredReplicas int32
+	if currDeploy.Spec.Replicas != nil {
+		desiredReplicas = *currDeploy.Spec.Replicas
+	}
+
+	if currDeploy.Status.ObservedGeneration >= currDeploy.Generation &&
+		currDeploy.Status.UpdatedReplicas == desiredReplicas &&
+		currDeploy.Status.AvailableReplicas == desiredReplicas &&
+		currDeploy.Status.Replicas == desiredReplicas {
+		rollout.State = appdbv1.ProvisioningStatusComplete
+	}
+
+	return &rollout
+}
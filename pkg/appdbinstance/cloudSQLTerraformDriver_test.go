@@ -0,0 +1,406 @@
+package appdbinstance
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/danisla/appdb-operator/pkg/cloudsqlproxy"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func newTestAppDBInstance(proxySpec appdbv1.CloudSQLProxySpec) *appdbv1.AppDBInstance {
+	return &appdbv1.AppDBInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"},
+		Spec: appdbv1.AppDBInstanceSpec{
+			Driver: appdbv1.AppDBDriver{
+				CloudSQLTerraform: &appdbv1.AppDBCloudSQLTerraformDriver{
+					Proxy: proxySpec,
+				},
+			},
+		},
+		Status: appdbv1.AppDBInstanceOperatorStatus{
+			CloudSQL: &appdbv1.AppDBInstanceCloudSQLStatus{
+				ConnectionName: "project:us-central1:example-1234",
+				Port:           3306,
+			},
+		},
+	}
+}
+
+func newTestTFApply() tfv1.Terraform {
+	return tfv1.Terraform{
+		Status: tfv1.TerraformOperatorStatus{
+			TFOutput: map[string]tfv1.TerraformOutputVar{
+				"proxy_sa_key":   tfv1.TerraformOutputVar{Value: base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`))},
+				"proxy_sa_email": tfv1.TerraformOutputVar{Value: "example-proxy@project.iam.gserviceaccount.com"},
+			},
+		},
+	}
+}
+
+func setupTestConfig(t *testing.T) {
+	prevConfig := config
+	config = &operator.Config{CloudSQLProxyImage: "gcr.io/cloudsql-docker/gce-proxy:1.16"}
+	t.Cleanup(func() {
+		config = prevConfig
+	})
+}
+
+func TestVerifyProxySpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    appdbv1.CloudSQLProxySpec
+		wantErr bool
+	}{
+		{"default", appdbv1.CloudSQLProxySpec{}, false},
+		{"replicas", appdbv1.CloudSQLProxySpec{Replicas: 2}, false},
+		{"negative replicas", appdbv1.CloudSQLProxySpec{Replicas: -1}, true},
+		{"sidecar workload identity without service accounts", appdbv1.CloudSQLProxySpec{Mode: appdbv1.CloudSQLProxyModeSidecar, Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity}, true},
+		{"sidecar workload identity", appdbv1.CloudSQLProxySpec{Mode: appdbv1.CloudSQLProxyModeSidecar, Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity, ServiceAccounts: []string{"default/app"}}, false},
+		{"invalid service account", appdbv1.CloudSQLProxySpec{Mode: appdbv1.CloudSQLProxyModeSidecar, Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity, ServiceAccounts: []string{"app"}}, true},
+	}
+
+	for _, tc := range tests {
+		err := verifyProxySpec(tc.spec)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error: %t, got: %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestMakeCloudSQLProxyReplicas(t *testing.T) {
+	setupTestConfig(t)
+
+	tests := []struct {
+		replicas int32
+		want     int32
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 3},
+	}
+
+	for _, tc := range tests {
+		proxy, err := makeCloudSQLProxy(newTestAppDBInstance(appdbv1.CloudSQLProxySpec{Replicas: tc.replicas}), newTestTFApply())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := *proxy.Deployment.Spec.Replicas; got != tc.want {
+			t.Errorf("replicas %d: expected %d Deployment replicas, got: %d", tc.replicas, tc.want, got)
+		}
+	}
+}
+
+func TestMakeCloudSQLProxyHardening(t *testing.T) {
+	setupTestConfig(t)
+
+	selector := map[string]string{"app": "example-proxy"}
+	customAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"db"}}},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		spec         appdbv1.CloudSQLProxySpec
+		wantAffinity *corev1.Affinity
+	}{
+		{"default affinity", appdbv1.CloudSQLProxySpec{}, makeCloudSQLProxyDefaultAffinity(selector)},
+		{"custom affinity", appdbv1.CloudSQLProxySpec{Affinity: customAffinity}, customAffinity},
+	}
+
+	for _, tc := range tests {
+		proxy, err := makeCloudSQLProxy(newTestAppDBInstance(tc.spec), newTestTFApply())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		podSpec := proxy.Deployment.Spec.Template.Spec
+
+		if reflect.DeepEqual(podSpec.Affinity, tc.wantAffinity) == false {
+			t.Errorf("%s: expected affinity %+v, got: %+v", tc.name, tc.wantAffinity, podSpec.Affinity)
+		}
+
+		container := podSpec.Containers[0]
+		sc := container.SecurityContext
+		if sc == nil || *sc.RunAsNonRoot != true || *sc.RunAsUser != cloudsqlproxy.DEFAULT_CLOUD_SQL_PROXY_RUN_AS_USER || *sc.AllowPrivilegeEscalation != false || *sc.ReadOnlyRootFilesystem != true {
+			t.Errorf("%s: expected restricted security context, got: %+v", tc.name, sc)
+		}
+		if sc != nil && (sc.Capabilities == nil || reflect.DeepEqual(sc.Capabilities.Drop, []corev1.Capability{"ALL"}) == false) {
+			t.Errorf("%s: expected all capabilities dropped, got: %+v", tc.name, sc.Capabilities)
+		}
+
+		for probeName, probe := range map[string]*corev1.Probe{"readiness": container.ReadinessProbe, "liveness": container.LivenessProbe} {
+			if probe == nil || probe.TCPSocket == nil || probe.TCPSocket.Port != intstr.FromString("sql") {
+				t.Errorf("%s: expected %s probe on the sql port, got: %+v", tc.name, probeName, probe)
+			}
+		}
+
+		if _, ok := container.Resources.Requests[corev1.ResourceCPU]; ok == false {
+			t.Errorf("%s: expected default resource requests, got: %v", tc.name, container.Resources.Requests)
+		}
+
+		pdb := proxy.PodDisruptionBudget
+		if pdb.Spec.MinAvailable == nil || *pdb.Spec.MinAvailable != intstr.FromInt(1) {
+			t.Errorf("%s: expected PodDisruptionBudget minAvailable 1, got: %v", tc.name, pdb.Spec.MinAvailable)
+		}
+		if pdb.Spec.Selector == nil || reflect.DeepEqual(pdb.Spec.Selector.MatchLabels, selector) == false {
+			t.Errorf("%s: expected PodDisruptionBudget selector %v, got: %v", tc.name, selector, pdb.Spec.Selector)
+		}
+	}
+}
+
+func TestMakeCloudSQLProxyDefaultAffinity(t *testing.T) {
+	selector := map[string]string{"app": "example-proxy"}
+	terms := makeCloudSQLProxyDefaultAffinity(selector).PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+
+	want := []struct {
+		topologyKey string
+		weight      int32
+	}{
+		{"failure-domain.beta.kubernetes.io/zone", 100},
+		{"kubernetes.io/hostname", 50},
+	}
+	if len(terms) != len(want) {
+		t.Fatalf("Expected %d anti-affinity terms, got: %d", len(want), len(terms))
+	}
+	for i, w := range want {
+		if terms[i].PodAffinityTerm.TopologyKey != w.topologyKey || terms[i].Weight != w.weight {
+			t.Errorf("Term %d: expected %s with weight %d, got: %s with weight %d", i, w.topologyKey, w.weight, terms[i].PodAffinityTerm.TopologyKey, terms[i].Weight)
+		}
+		if reflect.DeepEqual(terms[i].PodAffinityTerm.LabelSelector.MatchLabels, selector) == false {
+			t.Errorf("Term %d: expected selector %v, got: %v", i, selector, terms[i].PodAffinityTerm.LabelSelector)
+		}
+	}
+}
+
+func TestMakeCloudSQLProxyAuth(t *testing.T) {
+	setupTestConfig(t)
+
+	keyFile := cloudsqlproxy.CREDENTIALS_DIR + "/sa-key.json"
+	wiSidecar := appdbv1.CloudSQLProxySpec{Mode: appdbv1.CloudSQLProxyModeSidecar, Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity, ServiceAccounts: []string{"default/app"}}
+
+	tests := []struct {
+		name               string
+		spec               appdbv1.CloudSQLProxySpec
+		wantSecret         bool
+		wantServiceAccount bool
+		wantCredentialFile bool
+	}{
+		{"service account key", appdbv1.CloudSQLProxySpec{}, true, false, true},
+		{"workload identity", appdbv1.CloudSQLProxySpec{Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity}, false, true, false},
+		{"workload identity sidecar", wiSidecar, false, false, false},
+	}
+
+	for _, tc := range tests {
+		proxy, err := makeCloudSQLProxy(newTestAppDBInstance(tc.spec), newTestTFApply())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		podSpec := proxy.Deployment.Spec.Template.Spec
+
+		if (proxy.Secret != nil) != tc.wantSecret {
+			t.Errorf("%s: expected key Secret: %t, got: %v", tc.name, tc.wantSecret, proxy.Secret)
+		}
+		if (len(podSpec.Volumes) == 1) != tc.wantSecret {
+			t.Errorf("%s: expected key volume: %t, got: %v", tc.name, tc.wantSecret, podSpec.Volumes)
+		}
+
+		hasCredentialFile := false
+		for _, arg := range podSpec.Containers[0].Command {
+			if arg == "-credential_file="+keyFile {
+				hasCredentialFile = true
+			}
+		}
+		if hasCredentialFile != tc.wantCredentialFile {
+			t.Errorf("%s: expected -credential_file: %t, got: %v", tc.name, tc.wantCredentialFile, podSpec.Containers[0].Command)
+		}
+
+		if (proxy.ServiceAccount != nil) != tc.wantServiceAccount {
+			t.Fatalf("%s: expected ServiceAccount: %t, got: %v", tc.name, tc.wantServiceAccount, proxy.ServiceAccount)
+		}
+		if tc.wantServiceAccount == true {
+			// The proxy pods run as the Kubernetes service account bound to the proxy Google service account.
+			if got := proxy.ServiceAccount.Annotations["iam.gke.io/gcp-service-account"]; got != "example-proxy@project.iam.gserviceaccount.com" {
+				t.Errorf("%s: expected Workload Identity annotation example-proxy@project.iam.gserviceaccount.com, got: %q", tc.name, got)
+			}
+			if proxy.ServiceAccount.Name != "example-proxy" || podSpec.ServiceAccountName != "example-proxy" {
+				t.Errorf("%s: expected pods to run as example-proxy, got ServiceAccount %s and serviceAccountName %q", tc.name, proxy.ServiceAccount.Name, podSpec.ServiceAccountName)
+			}
+		} else if podSpec.ServiceAccountName != "" {
+			t.Errorf("%s: expected default serviceAccountName, got: %s", tc.name, podSpec.ServiceAccountName)
+		}
+	}
+}
+
+func TestMakeCloudSQLProxyMissingOutputs(t *testing.T) {
+	setupTestConfig(t)
+
+	for _, auth := range []appdbv1.CloudSQLProxyAuth{appdbv1.CloudSQLProxyAuthServiceAccountKey, appdbv1.CloudSQLProxyAuthWorkloadIdentity} {
+		if _, err := makeCloudSQLProxy(newTestAppDBInstance(appdbv1.CloudSQLProxySpec{Auth: auth}), tfv1.Terraform{}); err == nil {
+			t.Errorf("%s: expected error for missing TerraformApply outputs", auth)
+		}
+	}
+}
+
+func TestMakeTFVarsProxyAuth(t *testing.T) {
+	tests := []struct {
+		name                string
+		spec                appdbv1.CloudSQLProxySpec
+		wantProxyAuth       string
+		wantServiceAccounts string
+	}{
+		{"service account key", appdbv1.CloudSQLProxySpec{}, "", ""},
+		{"workload identity", appdbv1.CloudSQLProxySpec{Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity}, "workloadIdentity", "default/example-proxy"},
+		{"workload identity sidecar", appdbv1.CloudSQLProxySpec{Mode: appdbv1.CloudSQLProxyModeSidecar, Auth: appdbv1.CloudSQLProxyAuthWorkloadIdentity, ServiceAccounts: []string{"default/app", "team-a/worker"}}, "workloadIdentity", "default/app,team-a/worker"},
+	}
+
+	for _, tc := range tests {
+		tfvars, err := makeTFVars("appdbi-example", newTestAppDBInstance(tc.spec))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if tfvars["proxy_auth"] != tc.wantProxyAuth {
+			t.Errorf("%s: expected proxy_auth %q, got: %q", tc.name, tc.wantProxyAuth, tfvars["proxy_auth"])
+		}
+		if tfvars["k8s_service_accounts"] != tc.wantServiceAccounts {
+			t.Errorf("%s: expected k8s_service_accounts %q, got: %q", tc.name, tc.wantServiceAccounts, tfvars["k8s_service_accounts"])
+		}
+	}
+}
+
+func TestMakeTFVarsConnectivity(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        appdbv1.CloudSQLConnectivitySpec
+		wantNetwork string
+		wantSSL     string
+		wantErr     bool
+	}{
+		{"proxy", appdbv1.CloudSQLConnectivitySpec{}, "", "", false},
+		{"private IP", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIP, Network: "default"}, "default", "", false},
+		{"private IP proxy", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIPProxy, Network: "vpc"}, "vpc", "", false},
+		{"private IP with TLS", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIP, Network: "default", TLS: true}, "default", "true", false},
+		{"private IP without network", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIP}, "", "", true},
+		{"private IP proxy without network", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIPProxy}, "", "", true},
+	}
+
+	for _, tc := range tests {
+		parent := newTestAppDBInstance(appdbv1.CloudSQLProxySpec{})
+		parent.Spec.Driver.CloudSQLTerraform.Connectivity = tc.spec
+
+		tfvars, err := makeTFVars("appdbi-example", parent)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error: %t, got: %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		if tc.wantErr == true {
+			continue
+		}
+		if tfvars["private_network"] != tc.wantNetwork {
+			t.Errorf("%s: expected private_network %q, got: %q", tc.name, tc.wantNetwork, tfvars["private_network"])
+		}
+		if tfvars["require_ssl"] != tc.wantSSL {
+			t.Errorf("%s: expected require_ssl %q, got: %q", tc.name, tc.wantSSL, tfvars["require_ssl"])
+		}
+	}
+}
+
+func newTestBoundAppDB(namespace, name string) appdbv1.AppDB {
+	return appdbv1.AppDB{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       appdbv1.AppDBSpec{AppDBInstance: "default/example"},
+	}
+}
+
+func TestMakeCloudSQLProxyNetworkPolicy(t *testing.T) {
+	allowedNamespaces := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	monitoring := networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}}}
+
+	tests := []struct {
+		name              string
+		appdbs            []appdbv1.AppDB
+		allowedNamespaces *metav1.LabelSelector
+		allowedFrom       []networkingv1.NetworkPolicyPeer
+		wantPeers         []networkingv1.NetworkPolicyPeer
+	}{
+		{"no AppDBs denies all", nil, nil, nil, nil},
+		{
+			"local AppDBs, sorted and unique",
+			[]appdbv1.AppDB{newTestBoundAppDB("default", "b"), newTestBoundAppDB("default", "a"), newTestBoundAppDB("default", "a")},
+			nil, nil,
+			[]networkingv1.NetworkPolicyPeer{{PodSelector: makeAppDBNamePodSelector([]string{"a", "b"})}},
+		},
+		{
+			"remote AppDBs without allowed namespaces",
+			[]appdbv1.AppDB{newTestBoundAppDB("team-a", "c")},
+			nil, nil, nil,
+		},
+		{
+			"remote AppDBs in allowed namespaces",
+			[]appdbv1.AppDB{newTestBoundAppDB("default", "a"), newTestBoundAppDB("team-a", "c")},
+			allowedNamespaces, nil,
+			[]networkingv1.NetworkPolicyPeer{
+				{PodSelector: makeAppDBNamePodSelector([]string{"a"})},
+				{NamespaceSelector: allowedNamespaces, PodSelector: makeAppDBNamePodSelector([]string{"c"})},
+			},
+		},
+		{
+			"allowed from",
+			[]appdbv1.AppDB{newTestBoundAppDB("default", "a")},
+			nil, []networkingv1.NetworkPolicyPeer{monitoring},
+			[]networkingv1.NetworkPolicyPeer{{PodSelector: makeAppDBNamePodSelector([]string{"a"})}, monitoring},
+		},
+	}
+
+	for _, tc := range tests {
+		parent := newTestAppDBInstance(appdbv1.CloudSQLProxySpec{NetworkPolicy: true, AllowedFrom: tc.allowedFrom})
+		parent.Spec.AllowedNamespaces = tc.allowedNamespaces
+
+		netpol := makeCloudSQLProxyNetworkPolicy(parent, tc.appdbs)
+
+		if netpol.Name != "example-proxy" || reflect.DeepEqual(netpol.Spec.PodSelector.MatchLabels, map[string]string{"app": "example-proxy"}) == false {
+			t.Errorf("%s: expected policy example-proxy selecting the proxy pods, got: %s %v", tc.name, netpol.Name, netpol.Spec.PodSelector)
+		}
+		if reflect.DeepEqual(netpol.Spec.PolicyTypes, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}) == false {
+			t.Errorf("%s: expected Ingress policy type, got: %v", tc.name, netpol.Spec.PolicyTypes)
+		}
+
+		if tc.wantPeers == nil {
+			// A rule without peers would allow all sources.
+			if len(netpol.Spec.Ingress) != 0 {
+				t.Errorf("%s: expected no ingress rules, got: %+v", tc.name, netpol.Spec.Ingress)
+			}
+			continue
+		}
+		if len(netpol.Spec.Ingress) != 1 {
+			t.Fatalf("%s: expected 1 ingress rule, got: %+v", tc.name, netpol.Spec.Ingress)
+		}
+		rule := netpol.Spec.Ingress[0]
+		if reflect.DeepEqual(rule.From, tc.wantPeers) == false {
+			t.Errorf("%s: expected peers %+v, got: %+v", tc.name, tc.wantPeers, rule.From)
+		}
+		if len(rule.Ports) != 1 || *rule.Ports[0].Protocol != corev1.ProtocolTCP || *rule.Ports[0].Port != intstr.FromString("sql") {
+			t.Errorf("%s: expected the TCP sql port, got: %+v", tc.name, rule.Ports)
+		}
+	}
+}
+
+func TestMakeAppDBNamePodSelector(t *testing.T) {
+	selector := makeAppDBNamePodSelector([]string{"b", "a", "b"})
+	want := []metav1.LabelSelectorRequirement{{Key: appdbv1.AppDBNameLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}}}
+	if reflect.DeepEqual(selector.MatchExpressions, want) == false {
+		t.Errorf("Expected %+v, got: %+v", want, selector.MatchExpressions)
+	}
+}
@@ -0,0 +1,75 @@
+package appdbinstance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danisla/appdb-operator/pkg/hook"
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/standalone"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// childResources are the children of the AppDBInstance in the standalone manager mode, same as the childResources of the CompositeController in manifests/appdb-operator.yaml.
+var childResources = []standalone.ChildResource{
+	{APIVersion: "v1", Kind: "Secret", Resource: "secrets", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "v1", Kind: "Service", Resource: "services", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "apps/v1beta1", Kind: "Deployment", Resource: "deployments", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", Resource: "poddisruptionbudgets", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "v1", Kind: "ServiceAccount", Resource: "serviceaccounts", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy", Resource: "networkpolicies", UpdateStrategy: standalone.UpdateStrategyInPlace},
+	{APIVersion: "ctl.isla.solutions/v1", Kind: "TerraformApply", Resource: "terraformapplys", UpdateStrategy: standalone.UpdateStrategyOnDelete},
+	{APIVersion: "ctl.isla.solutions/v1", Kind: "TerraformPlan", Resource: "terraformplans", UpdateStrategy: standalone.UpdateStrategyOnDelete},
+}
+
+// MakeStandaloneController returns the AppDBInstance controller for the standalone manager mode.
+// AppDBInstances are also synced when an AppDB placed on them changes, to update the capacity status and the proxy network policy.
+func MakeStandaloneController() standalone.Controller {
+	return standalone.Controller{
+		Name:           "appdb-instance-operator",
+		ParentResource: kubev1.AppDBInstanceResource,
+		ParentType:     &appdbv1.AppDBInstance{},
+		ChildResources: childResources,
+		Watches: []standalone.Watch{
+			{Resource: kubev1.AppDBResource, Map: instanceForAppDB},
+		},
+		Sync: Sync,
+	}
+}
+
+// Sync is the sync hook of the AppDBInstance controller, it converts the request to the typed sync request of the controller.
+func Sync(ctx context.Context, request *hook.SyncRequest) (*hook.SyncResponse, error) {
+	var req SyncRequest
+	if err := request.Decode(&req); err != nil {
+		return nil, fmt.Errorf("Could not convert SyncRequest: %v", err)
+	}
+
+	return hook.SyncParent(ctx, request, eventRecorder, func(ctx context.Context) (interface{}, error) {
+		status, children, err := sync(ctx, ParentDBInstance, &req.Parent, &req.Children)
+		if err != nil || status == nil || children == nil {
+			return nil, err
+		}
+		return &SyncResponse{
+			Status:   *status,
+			Children: *children,
+		}, nil
+	})
+}
+
+// instanceForAppDB returns the AppDBInstance the AppDB is placed on.
+func instanceForAppDB(obj *unstructured.Unstructured) []types.NamespacedName {
+	var appdb appdbv1.AppDB
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &appdb); err != nil {
+		return nil
+	}
+
+	namespace, name := appdb.GetAppDBInstanceRef()
+	if name == "" {
+		return nil
+	}
+
+	return []types.NamespacedName{{Namespace: namespace, Name: name}}
+}
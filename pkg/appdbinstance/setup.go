@@ -0,0 +1,47 @@
+// Package appdbinstance is the controller for AppDBInstance parents.
+package appdbinstance
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/danisla/appdb-operator/pkg/events"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	"github.com/danisla/appdb-operator/pkg/server"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+)
+
+var (
+	config         *operator.Config
+	tfDriverConfig *tfdriverv1.TerraformDriverConfig
+	eventRecorder  *events.Recorder
+)
+
+// Setup configures the controller, it must be called before the first sync.
+func Setup(c *operator.Config, tfConfig *tfdriverv1.TerraformDriverConfig, recorder *events.Recorder) {
+	config = c
+	tfDriverConfig = tfConfig
+	eventRecorder = recorder
+}
+
+// ReadyChecks returns the checks for /readyz: the config of the enabled driver is valid.
+func ReadyChecks() []server.Check {
+	checks := make([]server.Check, 0)
+
+	if tfDriverConfig.Enabled() == true {
+		checks = append(checks, server.Check{
+			Name: "appdbinstance-driver",
+			Func: func() error {
+				if err := tfDriverConfig.Validate(); err != nil {
+					return err
+				}
+				if _, err := os.Stat(cloudSQLSourcePath); err != nil {
+					return fmt.Errorf("Terraform manifest not readable: %v", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	return checks
+}
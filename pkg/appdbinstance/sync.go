@@ -0,0 +1,458 @@
+package appdbinstance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/metrics"
+	"github.com/danisla/appdb-operator/pkg/syncerr"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"github.com/jinzhu/copier"
+)
+
+func sync(ctx context.Context, parentType ParentType, parent *appdbv1.AppDBInstance, children *AppDBInstanceChildren) (*appdbv1.AppDBInstanceOperatorStatus, *[]interface{}, error) {
+	logger := logging.FromContext(ctx)
+	var status appdbv1.AppDBInstanceOperatorStatus
+	copier.Copy(&status, &parent.Status)
+
+	desiredTFApplys := make(map[string]bool, 0)
+	desiredTFPlans := make(map[string]bool, 0)
+	desiredSecrets := make(map[string]bool, 0)
+	desiredDeployments := make(map[string]bool, 0)
+	desiredServices := make(map[string]bool, 0)
+	desiredPDBs := make(map[string]bool, 0)
+	desiredServiceAccounts := make(map[string]bool, 0)
+	desiredNetworkPolicies := make(map[string]bool, 0)
+	desiredChildren := make([]interface{}, 0)
+
+	// AppDBs placed on the instance, used for the capacity status and the proxy network policy.
+	appdbs, appdbsErr := getBoundAppDBs(parent)
+	if appdbsErr != nil {
+		logger.Errorf("Failed to list AppDBs placed on instance: %v", appdbsErr)
+	} else {
+		setCapacityStatus(parent, &status, appdbs)
+	}
+
+	if parent.Spec.Driver.CloudSQLTerraform != nil && tfDriverConfig.Enabled() == false {
+		return nil, nil, syncerr.Permanentf("The cloudSQLTerraform driver is disabled, set TF_BACKEND_BUCKET or the project of the operator")
+	}
+
+	if parent.Spec.Driver.CloudSQLTerraform != nil {
+
+		if err := verifyProxySpec(parent.Spec.Driver.CloudSQLTerraform.Proxy); err != nil {
+			return nil, nil, syncerr.InvalidSpec(err)
+		}
+
+		// IAM database authentication is enabled on the instance when an AppDB placed on it has spec.iamUsers.
+		// Retry instead of computing the change signature without the AppDBs, that would turn the flag off.
+		if appdbsErr != nil {
+			return nil, nil, syncerr.Transientf("Failed to list AppDBs placed on instance: %v", appdbsErr)
+		}
+		iamAuth := requiresIAMAuthentication(appdbs)
+
+		tfApplyName := fmt.Sprintf("appdbi-%s", parent.Name)
+		planRunning := false
+		planDeleted := false
+
+		if tfplan, ok := children.TerraformPlans[tfApplyName]; ok == true {
+
+			status.Provisioning = appdbv1.ProvisioningStatusPending
+
+			if status.CloudSQL == nil {
+				logger.Warnf("Found TerraformPlan in children, but status.CloudSQL was nil, re-sync collision.")
+				// Delete TerraformPlan and try again.
+				desiredTFPlans[tfApplyName] = true
+			} else {
+
+				// Handle terraform plan
+				mySig := makeTFSig(parent, iamAuth)
+				tfplanSig := tfplan.Annotations["appdb-parent-sig"]
+
+				if mySig == tfplanSig {
+					// TODO: something this throws a nil pointer dereference... maybe a resync collision.
+					status.CloudSQL.TFPlanPodName = tfplan.Status.PodName
+
+					planRunning = true
+
+					if tfplan.Status.PodStatus == "COMPLETED" {
+						// Check plan
+						if tfplan.Status.TFPlanDiff.Destroyed > 0 {
+							logger.Errorf("TerraformPlan contains destroy actions, skipping patch.")
+
+							// Retry in 60 seconds.
+							tfplanFishedAtTime, err := time.Parse(time.RFC3339, tfplan.Status.FinishedAt)
+							if err != nil {
+								logger.Warnf("Failed to parse tfplan finished at time: %v", err)
+							} else {
+								if time.Since(tfplanFishedAtTime).Seconds() > 60 {
+									logger.Infof("Retrying TerraformPlan")
+									metrics.IncTerraformRetry("TerraformPlan", "AppDBInstance")
+									tfdriverv1.RecordSpan(ctx, "TerraformPlan", tfplan)
+									// Setting desiredTFPlans to true will cause it to be omitted during the claim phase, therefore deleting it.
+									desiredTFPlans[tfApplyName] = true
+								}
+							}
+						} else {
+							logger.Infof("TerraformPlan contains no destroy actions, proceeding with update.")
+							tfdriverv1.RecordSpan(ctx, "TerraformPlan", tfplan)
+
+							// Setting desiredTFPlans to true will cause it to be omitted during the claim phase, therefore deleting it.
+							desiredTFPlans[tfApplyName] = true
+
+							tfapply, err := makeCloudSQLTerraform(tfApplyName, parent, iamAuth)
+							if err != nil {
+								logger.Errorf("Failed to generate TerraformApply spec for CloudSQL: %v", err)
+							} else {
+								// The TerraformApply is only created or updated here, so it carries the trace of this sync.
+								tfapply.Annotations = tracing.InjectAnnotations(ctx, tfapply.Annotations)
+
+								if currTFApply, ok := children.TerraformApplys[tfApplyName]; ok == true {
+									// found existing tfapply, apply changes to it.
+									_, span := tracing.Start(ctx, "kube UpdateTerraformApply", tracing.ObjectAttributes("TerraformApply", currTFApply.GetNamespace(), currTFApply.GetName())...)
+									err = config.KubeClient.UpdateTerraformApply(currTFApply, tfapply)
+									tracing.End(span, err)
+									if err != nil {
+										logger.Errorf("Failed to update the TerraformApply resource: %v", err)
+									} else {
+
+										status.CloudSQL = &appdbv1.AppDBInstanceCloudSQLStatus{
+											TFApplyName: tfapply.GetName(),
+											TFApplySig:  makeTFSig(parent, iamAuth),
+										}
+
+										desiredTFApplys[tfApplyName] = true
+										desiredChildren = append(desiredChildren, tfapply)
+									}
+								} else {
+									// No existing tfapply, create new one.
+									status.CloudSQL = &appdbv1.AppDBInstanceCloudSQLStatus{
+										TFApplyName: tfapply.GetName(),
+										TFApplySig:  makeTFSig(parent, iamAuth),
+									}
+
+									desiredTFApplys[tfApplyName] = true
+									desiredChildren = append(desiredChildren, tfapply)
+								}
+							}
+						}
+					} else if tfplan.Status.PodStatus == "FAILED" {
+						logger.Warnf("Failed to run TerraformPlan")
+					} else {
+						// Wait for plan to complete.
+					}
+				} else {
+					// Stale plan from a previous spec, delete it and create a new plan on the next sync.
+					logger.WithChild("TerraformPlan", tfApplyName).Infof("Found TerraformPlan with non-matching parent sig, deleting it")
+					// Setting desiredTFPlans to true will cause it to be omitted during the claim phase, therefore deleting it.
+					desiredTFPlans[tfApplyName] = true
+					planDeleted = true
+				}
+			}
+		}
+
+		if tfapply, ok := children.TerraformApplys[tfApplyName]; ok == true {
+			mySig := makeTFSig(parent, iamAuth)
+			tfapplySig := tfapply.Annotations["appdb-parent-sig"]
+
+			if mySig == tfapplySig {
+
+				status.CloudSQL.TFApplyPodName = tfapply.Status.PodName
+
+				if tfapply.Status.PodStatus == "COMPLETED" {
+					if parent.Status.Provisioning != appdbv1.ProvisioningStatusComplete {
+						tfdriverv1.RecordSpan(ctx, "TerraformApply", tfapply)
+					}
+					status.Provisioning = appdbv1.ProvisioningStatusComplete
+
+					// Get the "name" output variable.
+					if nameVar, ok := tfapply.Status.TFOutput["name"]; ok == false {
+						logger.WithChild("TerraformApply", tfapply.GetName()).Errorf("Output variable 'name' not found in status of TerraformApply")
+					} else {
+						status.CloudSQL.InstanceName = nameVar.Value
+					}
+
+					// Get the "connection" output variable.
+					if connVar, ok := tfapply.Status.TFOutput["connection"]; ok == false {
+						logger.WithChild("TerraformApply", tfapply.GetName()).Errorf("Output variable 'connection' not found in status of TerraformApply")
+					} else {
+						status.CloudSQL.ConnectionName = connVar.Value
+					}
+
+					// Get the "port" output variable.
+					if portVar, ok := tfapply.Status.TFOutput["port"]; ok == false {
+						logger.WithChild("TerraformApply", tfapply.GetName()).Errorf("Output variable 'port' not found in status of TerraformApply")
+					} else {
+						port, err := strconv.Atoi(portVar.Value)
+						if err != nil {
+							logger.Errorf("Output variable 'port' could not be parsed as int: %s", portVar.Value)
+						}
+						status.CloudSQL.Port = int32(port)
+					}
+
+					// Get the serviceAccountEmail output variable
+					if saEmail, ok := tfapply.Status.TFOutput["instance_sa_email"]; ok == false {
+						logger.WithChild("TerraformApply", tfapply.GetName()).Errorf("Output variable 'instance_sa_email' not found in status of TerraformApply")
+					} else {
+						status.CloudSQL.ServiceAccountEmail = saEmail.Value
+					}
+
+					// Get the private IP and server CA certificate output variables, these are empty unless the instance is on a VPC network.
+					if privateIPVar, ok := tfapply.Status.TFOutput["private_ip_address"]; ok == true {
+						status.CloudSQL.PrivateIPAddress = privateIPVar.Value
+					}
+					if serverCAVar, ok := tfapply.Status.TFOutput["server_ca_cert"]; ok == true {
+						status.CloudSQL.ServerCACert = serverCAVar.Value
+					}
+
+					if parent.Spec.Driver.CloudSQLTerraform.Connectivity.GetMode() == appdbv1.CloudSQLConnectivityModePrivateIP {
+						// Apps connect directly to the private IP of the instance.
+						// Setting the desired maps to true without appending the child will cause the proxy children to be omitted during the claim phase, therefore deleting them.
+						proxyName := makeCloudSQLProxyName(parent)
+						desiredSecrets[proxyName] = true
+						desiredServiceAccounts[proxyName] = true
+						desiredDeployments[proxyName] = true
+						desiredServices[proxyName] = true
+						desiredPDBs[proxyName] = true
+						desiredNetworkPolicies[proxyName] = true
+
+						status.CloudSQL.ProxyService = ""
+						status.CloudSQL.ProxySecret = ""
+						status.CloudSQL.ProxyServiceAccount = ""
+						status.CloudSQL.ProxyRollout = nil
+						status.CloudSQL.ProxyNetworkPolicy = ""
+
+						if status.CloudSQL.PrivateIPAddress == "" {
+							logger.WithChild("TerraformApply", tfapply.GetName()).Errorf("Output variable 'private_ip_address' not found in status of TerraformApply")
+						}
+
+						status.DBHost = status.CloudSQL.PrivateIPAddress
+						status.DBPort = status.CloudSQL.Port
+					} else {
+						// Create the Cloud SQL Proxy
+						proxy, err := makeCloudSQLProxy(parent, tfapply)
+						if err != nil {
+							logger.Errorf("Failed to generate cloud sql proxy spec: %v", err)
+						} else {
+
+							// The proxy children are regenerated on every sync so that changes to the spec or TerraformApply outputs are rolled out.
+							// CompositeController updateStrategy for these children is set to InPlace.
+							// Setting the desired map to true without appending the child will cause it to be omitted during the claim phase, therefore deleting it.
+							proxyName := makeCloudSQLProxyName(parent)
+
+							// Cloud SQL Proxy Service Account Key Secret
+							desiredSecrets[proxyName] = true
+							status.CloudSQL.ProxySecret = ""
+							if proxy.Secret != nil {
+								if _, ok := children.Secrets[proxy.Secret.GetName()]; ok == false {
+									logger.WithChild("Secret", proxy.Secret.GetName()).Infof("Creating Cloud SQL Proxy secret")
+								}
+								desiredChildren = append(desiredChildren, *proxy.Secret)
+								status.CloudSQL.ProxySecret = proxy.Secret.GetName()
+							}
+
+							// Cloud SQL Proxy Workload Identity Service Account
+							desiredServiceAccounts[proxyName] = true
+							status.CloudSQL.ProxyServiceAccount = ""
+							if proxy.ServiceAccount != nil {
+								if _, ok := children.ServiceAccounts[proxy.ServiceAccount.GetName()]; ok == false {
+									logger.WithChild("ServiceAccount", proxy.ServiceAccount.GetName()).Infof("Creating Cloud SQL Proxy service account")
+								}
+								desiredChildren = append(desiredChildren, *proxy.ServiceAccount)
+								status.CloudSQL.ProxyServiceAccount = proxy.ServiceAccount.GetName()
+							}
+
+							deploy := proxy.Deployment
+							svc := proxy.Service
+							pdb := proxy.PodDisruptionBudget
+
+							desiredDeployments[deploy.GetName()] = true
+							desiredServices[svc.GetName()] = true
+							desiredPDBs[pdb.GetName()] = true
+							desiredNetworkPolicies[proxyName] = true
+
+							if parent.Spec.Driver.CloudSQLTerraform.Proxy.GetMode() == appdbv1.CloudSQLProxyModeSidecar {
+								// The proxy is injected into app pods by the appdb-operator, the Deployment, Service and PodDisruptionBudget are deleted.
+								status.CloudSQL.ProxyService = ""
+								status.CloudSQL.ProxyRollout = nil
+								status.CloudSQL.ProxyNetworkPolicy = ""
+
+								status.DBHost = "127.0.0.1"
+								status.DBPort = status.CloudSQL.Port
+							} else {
+								// Cloud SQL Proxy Deployment
+								currDeploy, ok := children.Deployments[deploy.GetName()]
+								if ok == false {
+									logger.WithChild("Deployment", deploy.GetName()).Infof("Creating Cloud SQL Proxy deployment")
+								} else if currDeploy.Annotations["appdb-proxy-sig"] != deploy.Annotations["appdb-proxy-sig"] {
+									logger.WithChild("Deployment", deploy.GetName()).Infof("Updating Cloud SQL Proxy deployment")
+								}
+								desiredChildren = append(desiredChildren, deploy)
+
+								status.CloudSQL.ProxyRollout = makeCloudSQLProxyRolloutStatus(deploy, currDeploy, ok)
+
+								// Cloud SQL Proxy Service
+								if _, ok := children.Services[svc.GetName()]; ok == false {
+									logger.WithChild("Service", svc.GetName()).Infof("Creating Cloud SQL Proxy service")
+								}
+								desiredChildren = append(desiredChildren, svc)
+
+								// Cloud SQL Proxy PodDisruptionBudget
+								if *deploy.Spec.Replicas > 1 {
+									if _, ok := children.PodDisruptionBudgets[pdb.GetName()]; ok == false {
+										logger.WithChild("PodDisruptionBudget", pdb.GetName()).Infof("Creating Cloud SQL Proxy pod disruption budget")
+									}
+									desiredChildren = append(desiredChildren, pdb)
+								}
+
+								// Cloud SQL Proxy NetworkPolicy, regenerated on every sync to track the AppDBs bound to the instance.
+								status.CloudSQL.ProxyNetworkPolicy = ""
+								if parent.Spec.Driver.CloudSQLTerraform.Proxy.NetworkPolicy == true {
+									if appdbsErr != nil {
+										// Claim the existing policy until the AppDBs can be listed.
+										desiredNetworkPolicies[proxyName] = false
+									} else {
+										netpol := makeCloudSQLProxyNetworkPolicy(parent, appdbs)
+										if _, ok := children.NetworkPolicies[netpol.GetName()]; ok == false {
+											logger.WithChild("NetworkPolicy", netpol.GetName()).Infof("Creating Cloud SQL Proxy network policy")
+										}
+										desiredChildren = append(desiredChildren, netpol)
+									}
+									status.CloudSQL.ProxyNetworkPolicy = proxyName
+								}
+
+								status.CloudSQL.ProxyService = svc.GetName()
+
+								status.DBHost = fmt.Sprintf("%s.%s.svc.cluster.local", svc.GetName(), svc.GetNamespace())
+								status.DBPort = status.CloudSQL.Port
+							}
+						}
+					}
+
+				} else if tfapply.Status.PodStatus == "FAILED" {
+					if parent.Status.Provisioning != appdbv1.ProvisioningStatusFailed {
+						tfdriverv1.RecordSpan(ctx, "TerraformApply", tfapply)
+					}
+					status.Provisioning = appdbv1.ProvisioningStatusFailed
+				} else {
+					status.Provisioning = appdbv1.ProvisioningStatusPending
+				}
+			} else {
+				if planRunning == false && planDeleted == false {
+					// Patch tfapply with updated spec.
+					logger.Infof("Change detected, running TerraformPlan to preview changes.")
+
+					// CompositeController updateStrategy is set to OnDelete, which means we cannot update the child resource from the controller.
+					// Instead, update the TerraformApply with the dynamic client after the TerraformPlan is verified.
+
+					// Verify requested change won't trigger a destroy operation.
+					tfplan, err := makeCloudSQLTerraform(tfApplyName, parent, iamAuth)
+					if err != nil {
+						logger.Errorf("Failed to generate TerraformPlan spec to check breaking changes for CloudSQL: %v", err)
+					} else {
+						tfplan.TypeMeta.Kind = "TerraformPlan"
+						tfplan.Annotations = tracing.InjectAnnotations(ctx, tfplan.Annotations)
+
+						status.CloudSQL = &appdbv1.AppDBInstanceCloudSQLStatus{
+							TFPlanName: tfApplyName,
+							TFPlanSig:  makeTFSig(parent, iamAuth),
+						}
+
+						desiredTFPlans[tfApplyName] = true
+						desiredChildren = append(desiredChildren, tfplan)
+
+						logger.WithChild("TerraformPlan", tfApplyName).Infof("Created TerraformPlan")
+					}
+				}
+			}
+		} else {
+			if planRunning == false && planDeleted == false {
+				// Create new TerraformPlan first before provisioning DB instance.
+				tfplan, err := makeCloudSQLTerraform(tfApplyName, parent, iamAuth)
+				if err != nil {
+					logger.Errorf("Failed to generate TerraformPlan spec to check breaking changes for CloudSQL: %v", err)
+				} else {
+					tfplan.TypeMeta.Kind = "TerraformPlan"
+					tfplan.Annotations = tracing.InjectAnnotations(ctx, tfplan.Annotations)
+					status.CloudSQL = &appdbv1.AppDBInstanceCloudSQLStatus{
+						TFPlanName: tfApplyName,
+						TFPlanSig:  makeTFSig(parent, iamAuth),
+					}
+
+					desiredTFPlans[tfApplyName] = true
+					desiredChildren = append(desiredChildren, tfplan)
+
+					logger.WithChild("TerraformPlan", tfApplyName).Infof("Created TerraformPlan")
+				}
+			}
+		}
+
+		// Claim new terraformapplys else claim existing.
+		for _, o := range children.TerraformApplys {
+			if desiredTFApplys[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new terraformplans else claim existing.
+		for _, o := range children.TerraformPlans {
+			if desiredTFPlans[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new secrets else claim existing.
+		for _, o := range children.Secrets {
+			if desiredSecrets[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new deployments else claim existing.
+		for _, o := range children.Deployments {
+			if desiredDeployments[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new services else claim existing.
+		for _, o := range children.Services {
+			if desiredServices[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new pod disruption budgets else claim existing.
+		for _, o := range children.PodDisruptionBudgets {
+			if desiredPDBs[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new service accounts else claim existing.
+		for _, o := range children.ServiceAccounts {
+			if desiredServiceAccounts[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+
+		// Claim new network policies else claim existing.
+		for _, o := range children.NetworkPolicies {
+			if desiredNetworkPolicies[o.GetName()] == false {
+				desiredChildren = append(desiredChildren, o)
+			}
+		}
+	} else {
+		return nil, nil, syncerr.InvalidSpecf("Unsupported AppDBInstance driver, spec.driver.cloudSQLTerraform is required")
+	}
+
+	if status.Provisioning != "" && status.Provisioning != parent.Status.Provisioning {
+		recordProvisioningTransition(parent, status.Provisioning)
+	}
+
+	return &status, &desiredChildren, nil
+}
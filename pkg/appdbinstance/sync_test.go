@@ -0,0 +1,286 @@
+package appdbinstance
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/operator"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func setupSyncTest(t *testing.T, objs ...runtime.Object) {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	kubeClient := kubev1.NewClientForInterface(dynamicClient, 0, kubev1.AppDBResource, kubev1.AppDBInstanceResource, kubev1.AppDBInstanceClassResource)
+
+	stopCh := make(chan struct{})
+	if err := kubeClient.Start(stopCh); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	srcPath := filepath.Join(t.TempDir(), "main.tf")
+	if err := ioutil.WriteFile(srcPath, []byte(`variable "name" {}`), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	prevConfig, prevTFDriverConfig, prevSourcePath := config, tfDriverConfig, cloudSQLSourcePath
+	config = &operator.Config{KubeClient: kubeClient}
+	tfDriverConfig = &tfdriverv1.TerraformDriverConfig{BackendBucket: "project-appdb-operator", BackendPrefix: "terraform", MaxAttempts: 4}
+	cloudSQLSourcePath = srcPath
+	t.Cleanup(func() {
+		close(stopCh)
+		config, tfDriverConfig, cloudSQLSourcePath = prevConfig, prevTFDriverConfig, prevSourcePath
+	})
+}
+
+func newTestTerraform(kind, name, sig string) tfv1.Terraform {
+	return tfv1.Terraform{
+		TypeMeta: metav1.TypeMeta{APIVersion: "ctl.isla.solutions/v1", Kind: kind},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        name,
+			Annotations: map[string]string{"appdb-parent-sig": sig},
+		},
+	}
+}
+
+// findTerraform returns the Terraform child of the kind and the number of them in the desired children.
+func findTerraform(children []interface{}, kind string) (tfv1.Terraform, int) {
+	var found tfv1.Terraform
+	count := 0
+	for _, c := range children {
+		if tf, ok := c.(tfv1.Terraform); ok == true && tf.Kind == kind {
+			found = tf
+			count++
+		}
+	}
+	return found, count
+}
+
+// newTestProvisionedChildren returns the completed TerraformApply of the instance with the outputs of the Cloud SQL manifest.
+func newTestProvisionedChildren(parent *appdbv1.AppDBInstance) *AppDBInstanceChildren {
+	tfapply := newTestTerraform("TerraformApply", "appdbi-example", makeTFSig(parent, false))
+	tfapply.Status = newTestTFApply().Status
+	tfapply.Status.PodStatus = "COMPLETED"
+	for k, v := range map[string]string{
+		"name":               "example-1234",
+		"connection":         "project:us-central1:example-1234",
+		"port":               "3306",
+		"private_ip_address": "10.0.0.5",
+	} {
+		tfapply.Status.TFOutput[k] = tfv1.TerraformOutputVar{Value: v}
+	}
+	return &AppDBInstanceChildren{
+		TerraformApplys: map[string]tfv1.Terraform{"appdbi-example": tfapply},
+	}
+}
+
+// findChildren returns the desired children of the kind.
+func findChildren(children []interface{}, kind string) []interface{} {
+	found := make([]interface{}, 0)
+	for _, c := range children {
+		if reflect.ValueOf(c).FieldByName("TypeMeta").Interface().(metav1.TypeMeta).Kind == kind {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+func TestSyncProxyPodDisruptionBudget(t *testing.T) {
+	setupSyncTest(t)
+
+	tests := []struct {
+		name     string
+		replicas int32
+		wantPDB  bool
+	}{
+		{"default replicas", 0, false},
+		{"single replica", 1, false},
+		{"multiple replicas", 2, true},
+	}
+
+	for _, tc := range tests {
+		parent := newTestAppDBInstance(appdbv1.CloudSQLProxySpec{Replicas: tc.replicas})
+		parent.Status.Provisioning = appdbv1.ProvisioningStatusComplete
+
+		_, desired, err := sync(context.Background(), ParentDBInstance, parent, newTestProvisionedChildren(parent))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if got := len(findChildren(*desired, "Deployment")); got != 1 {
+			t.Errorf("%s: expected 1 Deployment, got: %d", tc.name, got)
+		}
+		// A PodDisruptionBudget with a single replica would block node drains.
+		if got := len(findChildren(*desired, "PodDisruptionBudget")) == 1; got != tc.wantPDB {
+			t.Errorf("%s: expected PodDisruptionBudget: %t, got: %t", tc.name, tc.wantPDB, got)
+		}
+	}
+}
+
+func TestSyncProxyNetworkPolicy(t *testing.T) {
+	objs := []runtime.Object{}
+	for _, o := range []appdbv1.AppDB{newTestBoundAppDB("default", "a"), newTestBoundAppDB("default", "other")} {
+		o.TypeMeta = metav1.TypeMeta{APIVersion: "ctl.isla.solutions/v1", Kind: "AppDB"}
+		if o.Name == "other" {
+			o.Spec.AppDBInstance = "other-instance"
+		}
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&o)
+		if err != nil {
+			t.Fatalf("Failed to convert object: %v", err)
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: data})
+	}
+	setupSyncTest(t, objs...)
+
+	tests := []struct {
+		name          string
+		networkPolicy bool
+		wantNetpol    bool
+	}{
+		{"disabled", false, false},
+		{"enabled", true, true},
+	}
+
+	for _, tc := range tests {
+		parent := newTestAppDBInstance(appdbv1.CloudSQLProxySpec{NetworkPolicy: tc.networkPolicy})
+		parent.Status.Provisioning = appdbv1.ProvisioningStatusComplete
+
+		status, desired, err := sync(context.Background(), ParentDBInstance, parent, newTestProvisionedChildren(parent))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		netpols := findChildren(*desired, "NetworkPolicy")
+		if got := len(netpols) == 1; got != tc.wantNetpol {
+			t.Fatalf("%s: expected NetworkPolicy: %t, got: %t", tc.name, tc.wantNetpol, got)
+		}
+		if got := status.CloudSQL.ProxyNetworkPolicy != ""; got != tc.wantNetpol {
+			t.Errorf("%s: expected status proxyNetworkPolicy: %t, got: %q", tc.name, tc.wantNetpol, status.CloudSQL.ProxyNetworkPolicy)
+		}
+		if tc.wantNetpol == false {
+			continue
+		}
+
+		// Only the AppDB bound to this instance is allowed.
+		netpol := netpols[0].(networkingv1.NetworkPolicy)
+		want := []networkingv1.NetworkPolicyPeer{{PodSelector: makeAppDBNamePodSelector([]string{"a"})}}
+		if len(netpol.Spec.Ingress) != 1 || reflect.DeepEqual(netpol.Spec.Ingress[0].From, want) == false {
+			t.Errorf("%s: expected peers %+v, got: %+v", tc.name, want, netpol.Spec.Ingress)
+		}
+	}
+}
+
+func TestSyncDeletesStaleTerraformPlan(t *testing.T) {
+	setupSyncTest(t)
+	ctx := context.Background()
+
+	parent := newTestAppDBInstance(appdbv1.CloudSQLProxySpec{})
+	parent.Status.Provisioning = appdbv1.ProvisioningStatusPending
+
+	// Both children were created from a previous spec.
+	children := &AppDBInstanceChildren{
+		TerraformApplys: map[string]tfv1.Terraform{"appdbi-example": newTestTerraform("TerraformApply", "appdbi-example", "old-sig")},
+		TerraformPlans:  map[string]tfv1.Terraform{"appdbi-example": newTestTerraform("TerraformPlan", "appdbi-example", "old-sig")},
+	}
+
+	_, desired, err := sync(ctx, ParentDBInstance, parent, children)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, n := findTerraform(*desired, "TerraformPlan"); n != 0 {
+		t.Errorf("Expected the stale TerraformPlan to be omitted, got: %d", n)
+	}
+	tfapply, n := findTerraform(*desired, "TerraformApply")
+	if n != 1 || tfapply.Annotations["appdb-parent-sig"] != "old-sig" {
+		t.Errorf("Expected the existing TerraformApply to be claimed, got: %d", n)
+	}
+
+	// The next sync, after metacontroller deleted the plan, creates a plan for the current spec.
+	delete(children.TerraformPlans, "appdbi-example")
+
+	status, desired, err := sync(ctx, ParentDBInstance, parent, children)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tfplan, n := findTerraform(*desired, "TerraformPlan")
+	if n != 1 {
+		t.Fatalf("Expected 1 TerraformPlan, got: %d", n)
+	}
+	if got, want := tfplan.Annotations["appdb-parent-sig"], makeTFSig(parent, false); got != want {
+		t.Errorf("Expected TerraformPlan parent sig %q, got: %q", want, got)
+	}
+	if status.CloudSQL.TFPlanName != "appdbi-example" {
+		t.Errorf("Expected status tfplanName appdbi-example, got: %q", status.CloudSQL.TFPlanName)
+	}
+	if _, n := findTerraform(*desired, "TerraformApply"); n != 1 {
+		t.Errorf("Expected 1 TerraformApply, got: %d", n)
+	}
+}
+
+func TestSyncConnectivity(t *testing.T) {
+	setupSyncTest(t)
+
+	tests := []struct {
+		name           string
+		connectivity   appdbv1.CloudSQLConnectivitySpec
+		wantProxy      bool
+		wantDBHost     string
+		wantPrivateArg bool
+	}{
+		{"proxy", appdbv1.CloudSQLConnectivitySpec{}, true, "example-proxy.default.svc.cluster.local", false},
+		{"private IP proxy", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIPProxy, Network: "default"}, true, "example-proxy.default.svc.cluster.local", true},
+		{"private IP", appdbv1.CloudSQLConnectivitySpec{Mode: appdbv1.CloudSQLConnectivityModePrivateIP, Network: "default"}, false, "10.0.0.5", false},
+	}
+
+	for _, tc := range tests {
+		parent := newTestAppDBInstance(appdbv1.CloudSQLProxySpec{NetworkPolicy: true})
+		parent.Spec.Driver.CloudSQLTerraform.Connectivity = tc.connectivity
+		parent.Status.Provisioning = appdbv1.ProvisioningStatusComplete
+
+		status, desired, err := sync(context.Background(), ParentDBInstance, parent, newTestProvisionedChildren(parent))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+
+		if status.DBHost != tc.wantDBHost || status.DBPort != 3306 {
+			t.Errorf("%s: expected db host %s:3306, got: %s:%d", tc.name, tc.wantDBHost, status.DBHost, status.DBPort)
+		}
+		if status.CloudSQL.PrivateIPAddress != "10.0.0.5" {
+			t.Errorf("%s: expected private IP status 10.0.0.5, got: %q", tc.name, status.CloudSQL.PrivateIPAddress)
+		}
+
+		// Apps connect directly to the private IP, the proxy children are omitted so that they are deleted.
+		for _, kind := range []string{"Deployment", "Service", "Secret", "NetworkPolicy"} {
+			if got := len(findChildren(*desired, kind)) == 1; got != tc.wantProxy {
+				t.Errorf("%s: expected proxy %s: %t, got: %t", tc.name, kind, tc.wantProxy, got)
+			}
+		}
+		if tc.wantProxy == false {
+			if status.CloudSQL.ProxyService != "" || status.CloudSQL.ProxySecret != "" || status.CloudSQL.ProxyRollout != nil {
+				t.Errorf("%s: expected proxy status to be cleared, got: %+v", tc.name, status.CloudSQL)
+			}
+			continue
+		}
+
+		deploy := findChildren(*desired, "Deployment")[0].(appsv1beta1.Deployment)
+		hasPrivateArg := false
+		for _, arg := range deploy.Spec.Template.Spec.Containers[0].Command {
+			if arg == "-ip_address_types=PRIVATE" {
+				hasPrivateArg = true
+			}
+		}
+		if hasPrivateArg != tc.wantPrivateArg {
+			t.Errorf("%s: expected -ip_address_types=PRIVATE: %t, got: %v", tc.name, tc.wantPrivateArg, deploy.Spec.Template.Spec.Containers[0].Command)
+		}
+	}
+}
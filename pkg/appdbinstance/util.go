@@ -0,0 +1,73 @@
+package appdbinstance
+
+import (
+	"fmt"
+	"strings"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getBoundAppDBs returns the AppDBs in all namespaces that reference the AppDBInstance.
+func getBoundAppDBs(parent *appdbv1.AppDBInstance) ([]appdbv1.AppDB, error) {
+	appdbs := make([]appdbv1.AppDB, 0)
+
+	allAppDBs, err := config.KubeClient.ListAppDBs()
+	if err != nil {
+		return appdbs, err
+	}
+
+	for _, appdb := range allAppDBs {
+		namespace, name := appdb.GetAppDBInstanceRef()
+		if namespace == parent.GetNamespace() && name == parent.GetName() {
+			appdbs = append(appdbs, appdb)
+		}
+	}
+
+	return appdbs, nil
+}
+
+// requiresIAMAuthentication returns true if any of the AppDBs has spec.iamUsers, which need IAM database authentication on the instance.
+func requiresIAMAuthentication(appdbs []appdbv1.AppDB) bool {
+	for _, appdb := range appdbs {
+		if len(appdb.Spec.IAMUsers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// setCapacityStatus sets the number of AppDBs placed on the instance, the sum of their size hints and the remaining capacity.
+func setCapacityStatus(parent *appdbv1.AppDBInstance, status *appdbv1.AppDBInstanceOperatorStatus, appdbs []appdbv1.AppDB) {
+	var load int32
+	for _, appdb := range appdbs {
+		load += appdb.Spec.GetSizeHint()
+	}
+
+	status.DatabaseCount = int32(len(appdbs))
+	status.DatabaseLoad = load
+	status.RemainingCapacity = nil
+
+	if parent.Spec.MaxDatabases > 0 {
+		remaining := parent.Spec.MaxDatabases - load
+		if remaining < 0 {
+			remaining = 0
+		}
+		status.RemainingCapacity = &remaining
+	}
+}
+
+// recordProvisioningTransition emits an Event on the parent for the provisioning status changing to newStatus, the Event is a Warning for FAILED.
+func recordProvisioningTransition(parent *appdbv1.AppDBInstance, newStatus appdbv1.ProvisioningStatus) {
+	eventType := corev1.EventTypeNormal
+	if newStatus == appdbv1.ProvisioningStatusFailed {
+		eventType = corev1.EventTypeWarning
+	}
+
+	from := string(parent.Status.Provisioning)
+	if from == "" {
+		from = "none"
+	}
+
+	eventRecorder.Transition(parent, "Provisioning", string(newStatus), eventType, fmt.Sprintf("Provisioning%s", strings.Title(strings.ToLower(string(newStatus)))), "Provisioning changed from %s to %s", from, newStatus)
+}
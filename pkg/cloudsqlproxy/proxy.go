@@ -0,0 +1,82 @@
+// Package cloudsqlproxy builds the Cloud SQL proxy container settings shared by the proxy Deployment of the AppDBInstance and the sidecar injected into the pods of an AppDB.
+package cloudsqlproxy
+
+import (
+	"fmt"
+	"strings"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	DEFAULT_CLOUD_SQL_PROXY_CPU_REQUEST    = "50m"
+	DEFAULT_CLOUD_SQL_PROXY_MEMORY_REQUEST = "64Mi"
+	DEFAULT_CLOUD_SQL_PROXY_RUN_AS_USER    = 65534 // nobody
+
+	// CREDENTIALS_DIR is the mount path of the service account key secret.
+	CREDENTIALS_DIR = "/var/run/secrets/cloudsql"
+)
+
+// Command returns the proxy command listening on addr:port for the instance, the key at credentialFile is used when set.
+func Command(connectionName string, addr string, port int32, connectivity appdbv1.CloudSQLConnectivitySpec, credentialFile string) []string {
+	cmdStr := fmt.Sprintf("/cloud_sql_proxy -instances=%s=tcp:%s:%d", connectionName, addr, port)
+
+	if connectivity.GetMode() == appdbv1.CloudSQLConnectivityModePrivateIPProxy {
+		cmdStr = fmt.Sprintf("%s -ip_address_types=PRIVATE", cmdStr)
+	}
+
+	if credentialFile != "" {
+		cmdStr = fmt.Sprintf("%s -credential_file=%s", cmdStr, credentialFile)
+	}
+
+	return strings.Split(cmdStr, " ")
+}
+
+// Image returns the image and pull policy of the proxy spec, or the defaults of the operator when not set.
+func Image(proxySpec appdbv1.CloudSQLProxySpec, defaultImage string, defaultPullPolicy corev1.PullPolicy) (string, corev1.PullPolicy) {
+	image := defaultImage
+	if proxySpec.Image != "" {
+		image = proxySpec.Image
+	}
+
+	imagePullPolicy := defaultPullPolicy
+	if proxySpec.ImagePullPolicy != "" {
+		imagePullPolicy = proxySpec.ImagePullPolicy
+	}
+
+	return image, imagePullPolicy
+}
+
+// Resources returns the resources of the proxy spec, with default requests when none are set.
+func Resources(proxySpec appdbv1.CloudSQLProxySpec) corev1.ResourceRequirements {
+	resources := proxySpec.Resources
+
+	if len(resources.Requests) == 0 {
+		resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DEFAULT_CLOUD_SQL_PROXY_CPU_REQUEST),
+			corev1.ResourceMemory: resource.MustParse(DEFAULT_CLOUD_SQL_PROXY_MEMORY_REQUEST),
+		}
+	}
+
+	return resources
+}
+
+// SecurityContext returns the restricted security context of the proxy container, it runs as nobody with a read-only root filesystem and no capabilities.
+func SecurityContext() *corev1.SecurityContext {
+	var runAsNonRoot = true
+	var runAsUser int64 = DEFAULT_CLOUD_SQL_PROXY_RUN_AS_USER
+	var allowPrivilegeEscalation = false
+	var readOnlyRootFilesystem = true
+
+	return &corev1.SecurityContext{
+		RunAsNonRoot:             &runAsNonRoot,
+		RunAsUser:                &runAsUser,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
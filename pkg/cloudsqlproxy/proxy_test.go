@@ -0,0 +1,93 @@
+package cloudsqlproxy
+
+import (
+	"reflect"
+	"testing"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           appdbv1.CloudSQLConnectivityMode
+		credentialFile string
+		want           []string
+	}{
+		{"proxy", appdbv1.CloudSQLConnectivityModeProxy, "", []string{"/cloud_sql_proxy", "-instances=p:r:i=tcp:127.0.0.1:3306"}},
+		{"private ip", appdbv1.CloudSQLConnectivityModePrivateIPProxy, "", []string{"/cloud_sql_proxy", "-instances=p:r:i=tcp:127.0.0.1:3306", "-ip_address_types=PRIVATE"}},
+		{"key", appdbv1.CloudSQLConnectivityModeProxy, "/var/run/secrets/cloudsql/sa-key.json", []string{"/cloud_sql_proxy", "-instances=p:r:i=tcp:127.0.0.1:3306", "-credential_file=/var/run/secrets/cloudsql/sa-key.json"}},
+	}
+
+	for _, tc := range tests {
+		got := Command("p:r:i", "127.0.0.1", 3306, appdbv1.CloudSQLConnectivitySpec{Mode: tc.mode}, tc.credentialFile)
+		if reflect.DeepEqual(got, tc.want) == false {
+			t.Errorf("%s: expected %v, got: %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           appdbv1.CloudSQLProxySpec
+		wantImage      string
+		wantPullPolicy corev1.PullPolicy
+	}{
+		{"defaults", appdbv1.CloudSQLProxySpec{}, "proxy:1", corev1.PullIfNotPresent},
+		{"image", appdbv1.CloudSQLProxySpec{Image: "proxy:2"}, "proxy:2", corev1.PullIfNotPresent},
+		{"pull policy", appdbv1.CloudSQLProxySpec{ImagePullPolicy: corev1.PullAlways}, "proxy:1", corev1.PullAlways},
+	}
+
+	for _, tc := range tests {
+		image, pullPolicy := Image(tc.spec, "proxy:1", corev1.PullIfNotPresent)
+		if image != tc.wantImage || pullPolicy != tc.wantPullPolicy {
+			t.Errorf("%s: expected %s %s, got: %s %s", tc.name, tc.wantImage, tc.wantPullPolicy, image, pullPolicy)
+		}
+	}
+}
+
+func TestResources(t *testing.T) {
+	got := Resources(appdbv1.CloudSQLProxySpec{})
+	if got.Requests.Cpu().Cmp(resource.MustParse(DEFAULT_CLOUD_SQL_PROXY_CPU_REQUEST)) != 0 {
+		t.Errorf("Expected default cpu request, got: %v", got.Requests.Cpu())
+	}
+
+	spec := appdbv1.CloudSQLProxySpec{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		},
+	}
+	got = Resources(spec)
+	if _, ok := got.Requests[corev1.ResourceCPU]; ok == true {
+		t.Errorf("Expected requests of the spec to be kept, got: %v", got.Requests)
+	}
+}
+
+func TestSecurityContext(t *testing.T) {
+	sc := SecurityContext()
+
+	if sc.RunAsNonRoot == nil || *sc.RunAsNonRoot != true {
+		t.Errorf("Expected runAsNonRoot, got: %v", sc.RunAsNonRoot)
+	}
+	if sc.RunAsUser == nil || *sc.RunAsUser != DEFAULT_CLOUD_SQL_PROXY_RUN_AS_USER {
+		t.Errorf("Expected runAsUser %d, got: %v", DEFAULT_CLOUD_SQL_PROXY_RUN_AS_USER, sc.RunAsUser)
+	}
+	if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation != false {
+		t.Errorf("Expected allowPrivilegeEscalation false, got: %v", sc.AllowPrivilegeEscalation)
+	}
+	if sc.ReadOnlyRootFilesystem == nil || *sc.ReadOnlyRootFilesystem != true {
+		t.Errorf("Expected readOnlyRootFilesystem, got: %v", sc.ReadOnlyRootFilesystem)
+	}
+	if sc.Capabilities == nil || reflect.DeepEqual(sc.Capabilities.Drop, []corev1.Capability{"ALL"}) == false {
+		t.Errorf("Expected all capabilities dropped, got: %v", sc.Capabilities)
+	}
+
+	// Each call returns a new context, so a caller can't change the one of another container.
+	*sc.RunAsUser = 0
+	if *SecurityContext().RunAsUser != DEFAULT_CLOUD_SQL_PROXY_RUN_AS_USER {
+		t.Errorf("Expected a new security context on each call")
+	}
+}
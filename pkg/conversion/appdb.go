@@ -0,0 +1,198 @@
+package conversion
+
+import (
+	"strings"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	appdbv2 "github.com/danisla/appdb-operator/pkg/types/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UserTypesAnnotation is added to v1 AppDBs to restore the order and types of spec.users when converting back to v2.
+// v1 keeps password users and IAM users in separate lists, so the annotation is only needed when they were interleaved in v2.
+const UserTypesAnnotation = "conversion.ctl.isla.solutions/v2-user-types"
+
+// AppDBToV2 converts a v1 AppDB to v2.
+func AppDBToV2(in *appdbv1.AppDB) *appdbv2.AppDB {
+	out := &appdbv2.AppDB{
+		TypeMeta: metav1.TypeMeta{APIVersion: appdbv2.SchemeGroupVersion.String(), Kind: "AppDB"},
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	delete(out.Annotations, UserTypesAnnotation)
+
+	out.Spec = appdbv2.AppDBSpec{
+		AppDBInstance:     in.Spec.AppDBInstance,
+		InstanceClassName: in.Spec.InstanceClassName,
+		SizeHint:          in.Spec.SizeHint,
+		DBName:            in.Spec.DBName,
+		Users:             usersToV2(in.Spec.Users, in.Spec.IAMUsers, in.Annotations[UserTypesAnnotation]),
+		LoadURL:           in.Spec.LoadURL,
+		Credentials:       in.Spec.Credentials.DeepCopy(),
+	}
+
+	status := in.Status.DeepCopy()
+	out.Status = appdbv2.AppDBStatus{
+		Provisioning:       status.Provisioning,
+		DBHost:             status.DBHost,
+		AppDBInstance:      status.AppDBInstance,
+		AppDBInstanceSig:   status.AppDBInstanceSig,
+		Placement:          status.Placement,
+		Vault:              status.Vault,
+		CredentialsSecrets: status.CredentialsSecrets,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	if status.CloudSQLDB != nil {
+		out.Status.DriverResources = []appdbv2.DriverResourceStatus{
+			{
+				Role:       appdbv2.DriverResourceRoleApply,
+				APIVersion: terraformAPIVersion,
+				Kind:       "TerraformApply",
+				Name:       status.CloudSQLDB.TFApplyName,
+				PodName:    status.CloudSQLDB.TFApplyPodName,
+				Sig:        status.CloudSQLDB.TFApplySig,
+			},
+		}
+	}
+
+	return out
+}
+
+// AppDBFromV2 converts a v2 AppDB to v1.
+func AppDBFromV2(in *appdbv2.AppDB) *appdbv1.AppDB {
+	out := &appdbv1.AppDB{
+		TypeMeta: metav1.TypeMeta{APIVersion: appdbv1.SchemeGroupVersion.String(), Kind: "AppDB"},
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	delete(out.Annotations, UserTypesAnnotation)
+
+	users, iamUsers, userTypes := usersFromV2(in.Spec.Users)
+	if userTypes != "" {
+		if out.Annotations == nil {
+			out.Annotations = make(map[string]string, 0)
+		}
+		out.Annotations[UserTypesAnnotation] = userTypes
+	}
+
+	out.Spec = appdbv1.AppDBSpec{
+		AppDBInstance:     in.Spec.AppDBInstance,
+		InstanceClassName: in.Spec.InstanceClassName,
+		SizeHint:          in.Spec.SizeHint,
+		DBName:            in.Spec.DBName,
+		Users:             users,
+		IAMUsers:          iamUsers,
+		LoadURL:           in.Spec.LoadURL,
+		Credentials:       in.Spec.Credentials.DeepCopy(),
+	}
+
+	status := in.Status.DeepCopy()
+	out.Status = appdbv1.AppDBOperatorStatus{
+		Provisioning:       status.Provisioning,
+		DBHost:             status.DBHost,
+		AppDBInstance:      status.AppDBInstance,
+		AppDBInstanceSig:   status.AppDBInstanceSig,
+		Placement:          status.Placement,
+		Vault:              status.Vault,
+		CredentialsSecrets: status.CredentialsSecrets,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+	if apply := appdbv2.GetDriverResource(status.DriverResources, appdbv2.DriverResourceRoleApply); apply != nil {
+		out.Status.CloudSQLDB = &appdbv1.AppDBCloudSQLDBStatus{
+			TFApplyName:    apply.Name,
+			TFApplyPodName: apply.PodName,
+			TFApplySig:     apply.Sig,
+		}
+	}
+
+	return out
+}
+
+// usersToV2 merges the v1 password and IAM users into the v2 users list.
+// Password users come first unless userTypes, from the UserTypesAnnotation, gives the original v2 order.
+func usersToV2(users []string, iamUsers []appdbv1.AppDBIAMUser, userTypes string) []appdbv2.AppDBUser {
+	if len(users)+len(iamUsers) == 0 {
+		return nil
+	}
+
+	types := make([]appdbv2.AppDBUserType, 0)
+	if userTypes != "" {
+		for _, t := range strings.Split(userTypes, ",") {
+			types = append(types, appdbv2.AppDBUserType(t))
+		}
+	}
+	if validUserTypes(types, len(users), len(iamUsers)) == false {
+		// Annotation is missing or the v1 users have changed since it was written.
+		types = make([]appdbv2.AppDBUserType, 0)
+		for range users {
+			// Password users keep the default type.
+			types = append(types, "")
+		}
+		for range iamUsers {
+			types = append(types, appdbv2.AppDBUserTypeIAMServiceAccount)
+		}
+	}
+
+	out := make([]appdbv2.AppDBUser, 0)
+	for _, t := range types {
+		if t == appdbv2.AppDBUserTypeIAMServiceAccount {
+			out = append(out, appdbv2.AppDBUser{
+				Type:           appdbv2.AppDBUserTypeIAMServiceAccount,
+				ServiceAccount: iamUsers[0].ServiceAccount,
+			})
+			iamUsers = iamUsers[1:]
+		} else {
+			out = append(out, appdbv2.AppDBUser{
+				Name: users[0],
+				Type: t,
+			})
+			users = users[1:]
+		}
+	}
+
+	return out
+}
+
+// usersFromV2 splits the v2 users into the v1 password and IAM users.
+// The returned user types are empty when the v1 lists alone convert back to the same v2 users.
+func usersFromV2(in []appdbv2.AppDBUser) ([]string, []appdbv1.AppDBIAMUser, string) {
+	var users []string
+	var iamUsers []appdbv1.AppDBIAMUser
+
+	types := make([]string, 0)
+	needTypes := false
+	for _, u := range in {
+		if u.GetType() == appdbv2.AppDBUserTypeIAMServiceAccount {
+			iamUsers = append(iamUsers, appdbv1.AppDBIAMUser{
+				Type:           appdbv1.AppDBUserTypeIAMServiceAccount,
+				ServiceAccount: u.ServiceAccount,
+			})
+		} else {
+			if len(iamUsers) > 0 || u.Type != "" {
+				// Password user after an IAM user, or with the default type given explicitly.
+				needTypes = true
+			}
+			users = append(users, u.Name)
+		}
+		types = append(types, string(u.Type))
+	}
+
+	if needTypes == false {
+		return users, iamUsers, ""
+	}
+	return users, iamUsers, strings.Join(types, ",")
+}
+
+// validUserTypes returns true if the user types list matches the number of v1 password and IAM users.
+func validUserTypes(types []appdbv2.AppDBUserType, numUsers, numIAMUsers int) bool {
+	if len(types) != numUsers+numIAMUsers {
+		return false
+	}
+	numIAM := 0
+	for _, t := range types {
+		if t == appdbv2.AppDBUserTypeIAMServiceAccount {
+			numIAM++
+		}
+	}
+	return numIAM == numIAMUsers
+}
@@ -0,0 +1,133 @@
+package conversion
+
+import (
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	appdbv2 "github.com/danisla/appdb-operator/pkg/types/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// terraformAPIVersion is the apiVersion of the TerraformApply and TerraformPlan resources created by the cloudSQLTerraform driver.
+const terraformAPIVersion = "ctl.isla.solutions/v1"
+
+// AppDBInstanceToV2 converts a v1 AppDBInstance to v2.
+func AppDBInstanceToV2(in *appdbv1.AppDBInstance) *appdbv2.AppDBInstance {
+	out := &appdbv2.AppDBInstance{
+		TypeMeta: metav1.TypeMeta{APIVersion: appdbv2.SchemeGroupVersion.String(), Kind: "AppDBInstance"},
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+
+	status := in.Status.DeepCopy()
+	out.Status = appdbv2.AppDBInstanceStatus{
+		Provisioning:       status.Provisioning,
+		DBHost:             status.DBHost,
+		DBPort:             status.DBPort,
+		DatabaseCount:      status.DatabaseCount,
+		DatabaseLoad:       status.DatabaseLoad,
+		RemainingCapacity:  status.RemainingCapacity,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	if cloudSQL := status.CloudSQL; cloudSQL != nil {
+		// Always set, so that an empty status.cloudSQL converts back to non-nil.
+		out.Status.Instance = &appdbv2.DatabaseInstanceStatus{
+			Name:                cloudSQL.InstanceName,
+			ConnectionName:      cloudSQL.ConnectionName,
+			ServiceAccountEmail: cloudSQL.ServiceAccountEmail,
+			Port:                cloudSQL.Port,
+			PrivateIPAddress:    cloudSQL.PrivateIPAddress,
+			ServerCACert:        cloudSQL.ServerCACert,
+		}
+
+		proxy := appdbv2.DatabaseProxyStatus{
+			Service:        cloudSQL.ProxyService,
+			Secret:         cloudSQL.ProxySecret,
+			ServiceAccount: cloudSQL.ProxyServiceAccount,
+			NetworkPolicy:  cloudSQL.ProxyNetworkPolicy,
+			Rollout:        cloudSQL.ProxyRollout,
+		}
+		if proxy != (appdbv2.DatabaseProxyStatus{}) {
+			out.Status.Proxy = &proxy
+		}
+
+		if cloudSQL.TFApplyName != "" || cloudSQL.TFApplyPodName != "" || cloudSQL.TFApplySig != "" {
+			out.Status.DriverResources = append(out.Status.DriverResources, appdbv2.DriverResourceStatus{
+				Role:       appdbv2.DriverResourceRoleApply,
+				APIVersion: terraformAPIVersion,
+				Kind:       "TerraformApply",
+				Name:       cloudSQL.TFApplyName,
+				PodName:    cloudSQL.TFApplyPodName,
+				Sig:        cloudSQL.TFApplySig,
+			})
+		}
+
+		if cloudSQL.TFPlanName != "" || cloudSQL.TFPlanPodName != "" || cloudSQL.TFPlanSig != "" {
+			out.Status.DriverResources = append(out.Status.DriverResources, appdbv2.DriverResourceStatus{
+				Role:       appdbv2.DriverResourceRolePlan,
+				APIVersion: terraformAPIVersion,
+				Kind:       "TerraformPlan",
+				Name:       cloudSQL.TFPlanName,
+				PodName:    cloudSQL.TFPlanPodName,
+				Sig:        cloudSQL.TFPlanSig,
+			})
+		}
+	}
+
+	return out
+}
+
+// AppDBInstanceFromV2 converts a v2 AppDBInstance to v1.
+func AppDBInstanceFromV2(in *appdbv2.AppDBInstance) *appdbv1.AppDBInstance {
+	out := &appdbv1.AppDBInstance{
+		TypeMeta: metav1.TypeMeta{APIVersion: appdbv1.SchemeGroupVersion.String(), Kind: "AppDBInstance"},
+	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+
+	status := in.Status.DeepCopy()
+	out.Status = appdbv1.AppDBInstanceOperatorStatus{
+		Provisioning:       status.Provisioning,
+		DBHost:             status.DBHost,
+		DBPort:             status.DBPort,
+		DatabaseCount:      status.DatabaseCount,
+		DatabaseLoad:       status.DatabaseLoad,
+		RemainingCapacity:  status.RemainingCapacity,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	if status.Instance == nil && status.Proxy == nil && len(status.DriverResources) == 0 {
+		return out
+	}
+
+	cloudSQL := &appdbv1.AppDBInstanceCloudSQLStatus{}
+	if status.Instance != nil {
+		cloudSQL.InstanceName = status.Instance.Name
+		cloudSQL.ConnectionName = status.Instance.ConnectionName
+		cloudSQL.ServiceAccountEmail = status.Instance.ServiceAccountEmail
+		cloudSQL.Port = status.Instance.Port
+		cloudSQL.PrivateIPAddress = status.Instance.PrivateIPAddress
+		cloudSQL.ServerCACert = status.Instance.ServerCACert
+	}
+	if status.Proxy != nil {
+		cloudSQL.ProxyService = status.Proxy.Service
+		cloudSQL.ProxySecret = status.Proxy.Secret
+		cloudSQL.ProxyServiceAccount = status.Proxy.ServiceAccount
+		cloudSQL.ProxyNetworkPolicy = status.Proxy.NetworkPolicy
+		cloudSQL.ProxyRollout = status.Proxy.Rollout
+	}
+	if apply := appdbv2.GetDriverResource(status.DriverResources, appdbv2.DriverResourceRoleApply); apply != nil {
+		cloudSQL.TFApplyName = apply.Name
+		cloudSQL.TFApplyPodName = apply.PodName
+		cloudSQL.TFApplySig = apply.Sig
+	}
+	if plan := appdbv2.GetDriverResource(status.DriverResources, appdbv2.DriverResourceRolePlan); plan != nil {
+		cloudSQL.TFPlanName = plan.Name
+		cloudSQL.TFPlanPodName = plan.PodName
+		cloudSQL.TFPlanSig = plan.Sig
+	}
+	out.Status.CloudSQL = cloudSQL
+
+	return out
+}
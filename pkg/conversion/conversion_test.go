@@ -0,0 +1,181 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	appdbv2 "github.com/danisla/appdb-operator/pkg/types/v2"
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	v1Version = appdbv1.SchemeGroupVersion.String()
+	v2Version = appdbv2.SchemeGroupVersion.String()
+)
+
+// loadExamples returns the AppDBs and AppDBInstances of the examples as JSON, normalized by a decode and encode of their v1 type.
+func loadExamples(t *testing.T) map[string][]byte {
+	files, err := filepath.Glob("../../examples/*/*.yaml")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("No examples found: %v", err)
+	}
+
+	fixtures := make(map[string][]byte, 0)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		for i, doc := range strings.Split(string(data), "\n---") {
+			jsonData, err := yaml.YAMLToJSON([]byte(doc))
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", file, err)
+			}
+
+			var typeMeta metav1.TypeMeta
+			if err := json.Unmarshal(jsonData, &typeMeta); err != nil || typeMeta.APIVersion != v1Version {
+				continue
+			}
+
+			var obj interface{}
+			switch typeMeta.Kind {
+			case "AppDB":
+				obj = &appdbv1.AppDB{}
+			case "AppDBInstance":
+				obj = &appdbv1.AppDBInstance{}
+			default:
+				continue
+			}
+			if err := json.Unmarshal(jsonData, obj); err != nil {
+				t.Fatalf("Failed to parse %s: %v", file, err)
+			}
+			normalized, err := json.Marshal(obj)
+			if err != nil {
+				t.Fatalf("Failed to encode %s: %v", file, err)
+			}
+			fixtures[fmt.Sprintf("%s#%d", filepath.Base(file), i)] = normalized
+		}
+	}
+
+	return fixtures
+}
+
+// roundTrip converts the object to the other version and back, and fails the test if the result differs from the object.
+func roundTrip(t *testing.T, name string, data []byte, otherVersion string) []byte {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		t.Fatalf("%s: Failed to parse object: %v", name, err)
+	}
+
+	converted, err := Convert(data, otherVersion)
+	if err != nil {
+		t.Fatalf("%s: Conversion to %s failed: %v", name, otherVersion, err)
+	}
+	back, err := Convert(converted, typeMeta.APIVersion)
+	if err != nil {
+		t.Fatalf("%s: Conversion to %s failed: %v", name, typeMeta.APIVersion, err)
+	}
+
+	var want, got interface{}
+	json.Unmarshal(data, &want)
+	json.Unmarshal(back, &got)
+	if reflect.DeepEqual(want, got) == false {
+		t.Errorf("%s: %s -> %s -> %s round trip changed the object\nwant: %s\ngot:  %s", name, typeMeta.APIVersion, otherVersion, typeMeta.APIVersion, data, back)
+	}
+
+	return converted
+}
+
+func TestRoundTripExamples(t *testing.T) {
+	for name, data := range loadExamples(t) {
+		// v1 -> v2 -> v1
+		v2Data := roundTrip(t, name, data, v2Version)
+
+		// v2 -> v1 -> v2
+		roundTrip(t, name, v2Data, v1Version)
+	}
+}
+
+func TestRoundTripExamplesWithStatus(t *testing.T) {
+	for name, data := range loadExamples(t) {
+		var typeMeta metav1.TypeMeta
+		json.Unmarshal(data, &typeMeta)
+
+		var obj interface{}
+		switch typeMeta.Kind {
+		case "AppDB":
+			appdb := &appdbv1.AppDB{}
+			json.Unmarshal(data, appdb)
+			appdb.Status = appdbv1.AppDBOperatorStatus{
+				Provisioning:  appdbv1.ProvisioningStatusComplete,
+				DBHost:        "example-proxy.default.svc.cluster.local",
+				AppDBInstance: "default/example",
+				CloudSQLDB: &appdbv1.AppDBCloudSQLDBStatus{
+					TFApplyName:    "appdb-sbtest",
+					TFApplyPodName: "appdb-sbtest-abcde",
+					TFApplySig:     "1234",
+				},
+			}
+			obj = appdb
+		case "AppDBInstance":
+			appdbi := &appdbv1.AppDBInstance{}
+			json.Unmarshal(data, appdbi)
+			appdbi.Status = appdbv1.AppDBInstanceOperatorStatus{
+				Provisioning: appdbv1.ProvisioningStatusComplete,
+				DBHost:       "example-proxy",
+				DBPort:       3306,
+				CloudSQL: &appdbv1.AppDBInstanceCloudSQLStatus{
+					InstanceName:   "example-1234",
+					ConnectionName: "project:us-central1:example-1234",
+					Port:           3306,
+					ProxyService:   "example-proxy",
+					ProxySecret:    "example-proxy-sa-key",
+					TFApplyName:    "appdbi-example",
+					TFApplySig:     "1234",
+					TFPlanName:     "appdbi-example-plan",
+					TFPlanSig:      "5678",
+				},
+			}
+			obj = appdbi
+		}
+
+		withStatus, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatalf("%s: Failed to encode object: %v", name, err)
+		}
+
+		v2Data := roundTrip(t, name, withStatus, v2Version)
+		roundTrip(t, name, v2Data, v1Version)
+	}
+}
+
+func TestRoundTripV2InterleavedUsers(t *testing.T) {
+	appdb := &appdbv2.AppDB{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v2Version, Kind: "AppDB"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sbtest"},
+		Spec: appdbv2.AppDBSpec{
+			AppDBInstance: "example",
+			DBName:        "sbtest",
+			Users: []appdbv2.AppDBUser{
+				{Type: appdbv2.AppDBUserTypeIAMServiceAccount, ServiceAccount: "sbtest@project.iam.gserviceaccount.com"},
+				{Name: "sbtest"},
+				{Name: "admin", Type: appdbv2.AppDBUserTypePassword},
+			},
+		},
+	}
+
+	data, err := json.Marshal(appdb)
+	if err != nil {
+		t.Fatalf("Failed to encode object: %v", err)
+	}
+
+	v1Data := roundTrip(t, "interleaved", data, v1Version)
+	roundTrip(t, "interleaved", v1Data, v2Version)
+}
@@ -0,0 +1,146 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	appdbv2 "github.com/danisla/appdb-operator/pkg/types/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ConversionReview is the apiextensions.k8s.io/v1beta1 request and response sent to a CRD conversion webhook.
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest is the list of objects to convert to the desired API version.
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse is the list of converted objects, in the same order as the request.
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// Handler returns the http handler for the CRD conversion webhook.
+func Handler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var review ConversionReview
+
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "Unsupported method\n")
+			return
+		}
+
+		reqBody, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Printf("[ERROR] Failed to read request body: %v", err)
+			return
+		}
+
+		err = json.Unmarshal(reqBody, &review)
+		if err != nil || review.Request == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Printf("[ERROR] Could not parse ConversionReview: %v", err)
+			return
+		}
+
+		review.Response = convertObjects(review.Request)
+		review.Response.UID = review.Request.UID
+		review.Request = nil
+
+		data, err := json.Marshal(review)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Printf("[ERROR] Could not generate ConversionReview response: %v", err)
+			return
+		}
+		w.Write(data)
+	}
+}
+
+func convertObjects(req *ConversionRequest) *ConversionResponse {
+	resp := &ConversionResponse{
+		ConvertedObjects: make([]runtime.RawExtension, 0),
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+
+	for _, obj := range req.Objects {
+		data, err := Convert(obj.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			log.Printf("[ERROR] Conversion to %s failed: %v", req.DesiredAPIVersion, err)
+			return &ConversionResponse{
+				ConvertedObjects: make([]runtime.RawExtension, 0),
+				Result: metav1.Status{
+					Status:  metav1.StatusFailure,
+					Message: err.Error(),
+				},
+			}
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: data})
+	}
+
+	return resp
+}
+
+// Convert converts the JSON encoded AppDB or AppDBInstance to the desired API version.
+func Convert(data []byte, desiredAPIVersion string) ([]byte, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return nil, fmt.Errorf("Failed to parse object: %v", err)
+	}
+
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return data, nil
+	}
+
+	v1Version := appdbv1.SchemeGroupVersion.String()
+	v2Version := appdbv2.SchemeGroupVersion.String()
+
+	var out interface{}
+	switch {
+	case typeMeta.Kind == "AppDB" && typeMeta.APIVersion == v1Version && desiredAPIVersion == v2Version:
+		var in appdbv1.AppDB
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s %s: %v", typeMeta.APIVersion, typeMeta.Kind, err)
+		}
+		out = AppDBToV2(&in)
+	case typeMeta.Kind == "AppDB" && typeMeta.APIVersion == v2Version && desiredAPIVersion == v1Version:
+		var in appdbv2.AppDB
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s %s: %v", typeMeta.APIVersion, typeMeta.Kind, err)
+		}
+		out = AppDBFromV2(&in)
+	case typeMeta.Kind == "AppDBInstance" && typeMeta.APIVersion == v1Version && desiredAPIVersion == v2Version:
+		var in appdbv1.AppDBInstance
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s %s: %v", typeMeta.APIVersion, typeMeta.Kind, err)
+		}
+		out = AppDBInstanceToV2(&in)
+	case typeMeta.Kind == "AppDBInstance" && typeMeta.APIVersion == v2Version && desiredAPIVersion == v1Version:
+		var in appdbv2.AppDBInstance
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s %s: %v", typeMeta.APIVersion, typeMeta.Kind, err)
+		}
+		out = AppDBInstanceFromV2(&in)
+	default:
+		return nil, fmt.Errorf("Unsupported conversion of %s %s to %s", typeMeta.APIVersion, typeMeta.Kind, desiredAPIVersion)
+	}
+
+	return json.Marshal(out)
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/client/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// DEFAULT_DEDUP_TTL is how long the last recorded value of a transition is remembered, it must be longer than the resync period of the CompositeControllers.
+	DEFAULT_DEDUP_TTL = 1 * time.Hour
+)
+
+// Recorder emits Kubernetes Events on the parent objects for state transitions.
+// A sync may compute the same transition more than once when the status write of the previous sync has not been observed yet, so
+// transitions to the value last recorded for the same object and key are dropped.
+type Recorder struct {
+	recorder  record.EventRecorder
+	ttl       time.Duration
+	mu        sync.Mutex
+	last      map[string]lastTransition
+	lastSweep time.Time
+}
+
+type lastTransition struct {
+	value string
+	at    time.Time
+}
+
+// NewRecorder creates a Recorder that writes Events with the clientset, component is the source of the Events.
+func NewRecorder(clientset kubernetes.Interface, component string) *Recorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	return NewRecorderFor(broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: component}))
+}
+
+// NewRecorderFor creates a Recorder from an existing EventRecorder, for example, the fake recorder.
+func NewRecorderFor(recorder record.EventRecorder) *Recorder {
+	return &Recorder{
+		recorder:  recorder,
+		ttl:       DEFAULT_DEDUP_TTL,
+		last:      make(map[string]lastTransition, 0),
+		lastSweep: time.Now(),
+	}
+}
+
+// Transition records an Event on the object when value differs from the last value recorded for the object and key.
+// eventType is one of corev1.EventTypeNormal or corev1.EventTypeWarning.
+func (r *Recorder) Transition(obj runtime.Object, key, value, eventType, reason, msgfmt string, fmtargs ...interface{}) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	id := fmt.Sprintf("%s/%s", accessor.GetUID(), key)
+
+	r.mu.Lock()
+	now := time.Now()
+	if now.Sub(r.lastSweep) > r.ttl {
+		// Drop the transitions of deleted objects.
+		for k, t := range r.last {
+			if now.Sub(t.at) > r.ttl {
+				delete(r.last, k)
+			}
+		}
+		r.lastSweep = now
+	}
+	if t, ok := r.last[id]; ok == true && t.value == value && now.Sub(t.at) <= r.ttl {
+		r.mu.Unlock()
+		return
+	}
+	r.last[id] = lastTransition{value: value, at: now}
+	r.mu.Unlock()
+
+	r.recorder.Eventf(obj, eventType, reason, msgfmt, fmtargs...)
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func newTestRecorder() (*Recorder, *record.FakeRecorder) {
+	fake := record.NewFakeRecorder(10)
+	return NewRecorderFor(fake), fake
+}
+
+func newTestAppDB(name string) *appdbv1.AppDB {
+	return &appdbv1.AppDB{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID("uid-" + name)}}
+}
+
+// drain returns the events recorded so far.
+func drain(fake *record.FakeRecorder) []string {
+	events := make([]string, 0)
+	for {
+		select {
+		case e := <-fake.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestTransitionDedup(t *testing.T) {
+	r, fake := newTestRecorder()
+	a := newTestAppDB("a")
+
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned %s", "a")
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned %s", "a")
+
+	if got := drain(fake); len(got) != 1 || got[0] != "Normal Provisioned Provisioned a" {
+		t.Fatalf("Expected the repeated transition within the TTL to be dropped, got: %v", got)
+	}
+
+	// A new value is recorded, and so is a return to the previous value.
+	r.Transition(a, "Provisioning", "Failed", corev1.EventTypeWarning, "ProvisionFailed", "Failed")
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned %s", "a")
+	if got := drain(fake); len(got) != 2 {
+		t.Errorf("Expected 2 events for changed values, got: %v", got)
+	}
+}
+
+func TestTransitionAfterTTL(t *testing.T) {
+	r, fake := newTestRecorder()
+	a := newTestAppDB("a")
+
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned")
+
+	// Age the recorded transition past the TTL.
+	id := "uid-a/Provisioning"
+	r.last[id] = lastTransition{value: r.last[id].value, at: time.Now().Add(-r.ttl - time.Second)}
+
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned")
+
+	if got := drain(fake); len(got) != 2 {
+		t.Errorf("Expected the same transition to be recorded again after the TTL, got: %v", got)
+	}
+}
+
+func TestTransitionPerObject(t *testing.T) {
+	r, fake := newTestRecorder()
+	a, b := newTestAppDB("a"), newTestAppDB("b")
+
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned")
+	r.Transition(b, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned")
+	// Other keys of the same object are not deduplicated either.
+	r.Transition(a, "Synced", "Complete", corev1.EventTypeNormal, "Synced", "Synced")
+
+	if got := drain(fake); len(got) != 3 {
+		t.Errorf("Expected an event per object and key, got: %v", got)
+	}
+}
+
+func TestTransitionSweep(t *testing.T) {
+	r, _ := newTestRecorder()
+	a, b := newTestAppDB("a"), newTestAppDB("b")
+
+	r.Transition(a, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned")
+	r.last["uid-a/Provisioning"] = lastTransition{value: "Complete", at: time.Now().Add(-r.ttl - time.Second)}
+	r.lastSweep = time.Now().Add(-r.ttl - time.Second)
+
+	r.Transition(b, "Provisioning", "Complete", corev1.EventTypeNormal, "Provisioned", "Provisioned")
+
+	if _, ok := r.last["uid-a/Provisioning"]; ok == true {
+		t.Errorf("Expected the expired transition to be swept")
+	}
+	if _, ok := r.last["uid-b/Provisioning"]; ok == false {
+		t.Errorf("Expected the new transition to be kept")
+	}
+}
@@ -0,0 +1,84 @@
+package hook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+)
+
+// Handler returns the sync hook of a CompositeController for parents of the kind.
+// Requests that can't be parsed or are for another kind are rejected with 400, sync errors are returned with 503 so that metacontroller retries the sync.
+func Handler(kind string, sync SyncFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req SyncRequest
+
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "Unsupported method\n")
+			return
+		}
+
+		requestID := r.Header.Get("X-Request-Id")
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+		}
+		logger := logging.New().With(logging.KeyRequestID, requestID)
+
+		reqBody, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			logger.Errorf("Failed to read request body: %v", err)
+			return
+		}
+
+		if logging.HTTPDebug() == true {
+			// The children in the request contain the credentials and proxy service account key Secrets, dump the headers and the redacted body only.
+			reqDump, _ := httputil.DumpRequest(r, false)
+			logger.With("headers", string(reqDump)).With("body", string(logging.RedactSecrets(reqBody))).Infof("HTTP request %s %s", r.Method, r.URL.String())
+		}
+
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			logger.Errorf("Could not parse SyncRequest: %v", err)
+			http.Error(w, fmt.Sprintf("Could not parse SyncRequest: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if req.Parent == nil || req.Parent.GetKind() != kind {
+			parentKind := ""
+			if req.Parent != nil {
+				parentKind = req.Parent.GetKind()
+			}
+			logger.Errorf("Unsupported parent kind: %s", parentKind)
+			http.Error(w, fmt.Sprintf("Unsupported parent kind: %s", parentKind), http.StatusBadRequest)
+			return
+		}
+		req.RequestID = requestID
+
+		logger = logger.ForObject(req.Parent.GetKind(), req.Parent.GetNamespace(), req.Parent.GetName())
+		ctx := logging.NewContext(tracing.ContextFromRequest(r.Context(), r), logger)
+
+		resp, err := sync(ctx, &req)
+		if err != nil {
+			// metacontroller retries the sync with backoff, the status and children are left unchanged.
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			logger.Errorf("Could not generate SyncResponse: %v", err)
+			return
+		}
+		w.Write(data)
+
+		if logging.HTTPDebug() == true {
+			logger.With("body", string(logging.RedactSecrets(data))).Infof("JSON response %s %s", r.Method, r.URL.String())
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/events"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/metrics"
+	"github.com/danisla/appdb-operator/pkg/syncerr"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// ParentSyncFunc computes the typed sync response of the controller, the response is converted with NewSyncResponse.
+type ParentSyncFunc func(ctx context.Context) (interface{}, error)
+
+// SyncParent runs the sync of the parent with a span and the sync metrics, it is shared by the controllers and by the webhook and standalone manager modes.
+// The Synced condition of the status is True after a successful sync. Permanent and invalid spec errors are reported on the Synced condition with
+// the observed children, transient errors are returned to be retried. A sync that returns neither a response nor an error is a permanent error.
+func SyncParent(ctx context.Context, request *SyncRequest, recorder *events.Recorder, sync ParentSyncFunc) (*SyncResponse, error) {
+	kind := request.Parent.GetKind()
+	ctx, span := tracing.Start(ctx, fmt.Sprintf("sync %s", kind),
+		append(tracing.ObjectAttributes(kind, request.Parent.GetNamespace(), request.Parent.GetName()), attribute.String("appdb.request_id", request.RequestID))...)
+	logger := logging.FromContext(ctx)
+	if span.SpanContext().IsValid() {
+		logger = logger.With(logging.KeyTraceID, span.SpanContext().TraceID().String())
+		ctx = logging.NewContext(ctx, logger)
+	}
+
+	syncStart := time.Now()
+	var resp *SyncResponse
+	result, err := sync(ctx)
+	if err == nil {
+		if result == nil {
+			err = syncerr.Permanentf("Sync of %s/%s returned no status", kind, request.Parent.GetName())
+		} else if resp, err = NewSyncResponse(result); err != nil {
+			err = syncerr.Permanentf("Could not convert SyncResponse: %v", err)
+		}
+	}
+	metrics.ObserveSync(kind, syncStart, err)
+	tracing.End(span, err)
+
+	if err != nil {
+		logger.With("reason", string(syncerr.ReasonOf(err))).Errorf("Could not sync state: %v", err)
+		if syncerr.IsTransient(err) {
+			return nil, err
+		}
+		// Retrying will not help, report the error on the status and keep the observed children.
+		reason := syncerr.ReasonOf(err)
+		if recorder != nil {
+			recorder.Transition(request.Parent, string(appdbv1.ConditionTypeSynced), fmt.Sprintf("%s: %v", reason, err), corev1.EventTypeWarning, string(reason), "%v", err)
+		}
+		resp = &SyncResponse{
+			Status:   request.ParentStatus(),
+			Children: request.ObservedChildren(),
+		}
+	}
+
+	if condErr := setSyncedCondition(resp.Status, request.ParentStatus(), err); condErr != nil {
+		return nil, fmt.Errorf("Could not set Synced condition: %v", condErr)
+	}
+
+	return resp, nil
+}
+
+// ParentStatus returns a copy of the status of the parent.
+func (r *SyncRequest) ParentStatus() map[string]interface{} {
+	status, ok := r.Parent.Object["status"].(map[string]interface{})
+	if ok == false {
+		return make(map[string]interface{}, 0)
+	}
+	return runtime.DeepCopyJSON(status)
+}
+
+// ObservedChildren returns the observed children of the parent, sorted by key and name.
+func (r *SyncRequest) ObservedChildren() []*unstructured.Unstructured {
+	children := make([]*unstructured.Unstructured, 0)
+
+	keys := make([]string, 0, len(r.Children))
+	for key := range r.Children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		names := make([]string, 0, len(r.Children[key]))
+		for name := range r.Children[key] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if child := r.Children[key][name]; child != nil {
+				children = append(children, child.DeepCopy())
+			}
+		}
+	}
+
+	return children
+}
+
+// SetSyncedCondition sets the Synced condition in the conditions, True when syncErr is nil, False with the class of the error as the reason otherwise.
+func SetSyncedCondition(conditions []appdbv1.AppDBCondition, syncErr error) []appdbv1.AppDBCondition {
+	tNow := metav1.NewTime(time.Now())
+
+	synced := appdbv1.AppDBCondition{
+		Type:               appdbv1.ConditionTypeSynced,
+		LastTransitionTime: tNow,
+	}
+	newConditions := make([]appdbv1.AppDBCondition, 0)
+	for _, condition := range conditions {
+		if condition.Type == appdbv1.ConditionTypeSynced {
+			synced = condition
+			continue
+		}
+		newConditions = append(newConditions, condition)
+	}
+
+	newStatus := appdbv1.ConditionTrue
+	synced.Reason = ""
+	synced.Message = ""
+	if syncErr != nil {
+		newStatus = appdbv1.ConditionFalse
+		synced.Reason = string(syncerr.ReasonOf(syncErr))
+		synced.Message = syncErr.Error()
+	}
+	if synced.Status != newStatus {
+		synced.LastTransitionTime = tNow
+	}
+	synced.Status = newStatus
+	synced.LastProbeTime = tNow
+
+	return append(newConditions, synced)
+}
+
+// setSyncedCondition sets the Synced condition in status.conditions of the response status.
+// The Synced condition of the parent status is used when the sync of the controller did not copy it to the response status.
+func setSyncedCondition(status map[string]interface{}, parentStatus map[string]interface{}, syncErr error) error {
+	var conditions, parentConditions []appdbv1.AppDBCondition
+	if err := convertJSON(status["conditions"], &conditions); err != nil {
+		return err
+	}
+	if err := convertJSON(parentStatus["conditions"], &parentConditions); err != nil {
+		return err
+	}
+
+	found := false
+	for _, condition := range conditions {
+		if condition.Type == appdbv1.ConditionTypeSynced {
+			found = true
+			break
+		}
+	}
+	if found == false {
+		for _, condition := range parentConditions {
+			if condition.Type == appdbv1.ConditionTypeSynced {
+				conditions = append(conditions, condition)
+			}
+		}
+	}
+
+	var out []interface{}
+	if err := convertJSON(SetSyncedCondition(conditions, syncErr), &out); err != nil {
+		return err
+	}
+	status["conditions"] = out
+
+	return nil
+}
+
+func convertJSON(in interface{}, out interface{}) error {
+	if in == nil {
+		return nil
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	// Integers are decoded as int64 like the objects from the informer cache.
+	return utiljson.Unmarshal(data, out)
+}
@@ -0,0 +1,379 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/danisla/appdb-operator/pkg/events"
+	"github.com/danisla/appdb-operator/pkg/syncerr"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+type testSyncResponse struct {
+	Status   testStatus    `json:"status"`
+	Children []interface{} `json:"children"`
+}
+
+type testStatus struct {
+	Provisioning string                   `json:"provisioning,omitempty"`
+	Conditions   []appdbv1.AppDBCondition `json:"conditions,omitempty"`
+}
+
+func newTestRequest() *SyncRequest {
+	parent := &unstructured.Unstructured{}
+	parent.SetAPIVersion("ctl.isla.solutions/v1")
+	parent.SetKind("AppDB")
+	parent.SetNamespace("default")
+	parent.SetName("db1")
+	parent.SetUID("uid-1")
+	parent.Object["status"] = map[string]interface{}{
+		"provisioning": "PENDING",
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "DBCreateComplete", "status": "True"},
+		},
+	}
+
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetName("appdb-db1")
+
+	tfapply := &unstructured.Unstructured{}
+	tfapply.SetAPIVersion("ctl.isla.solutions/v1")
+	tfapply.SetKind("TerraformApply")
+	tfapply.SetName("appdb-inst1-db1")
+
+	return &SyncRequest{
+		Parent: parent,
+		Children: map[string]map[string]*unstructured.Unstructured{
+			"Secret.v1":                            {"appdb-db1": secret},
+			"Terraformapply.ctl.isla.solutions/v1": {"appdb-inst1-db1": tfapply},
+		},
+		RequestID: "test",
+	}
+}
+
+func findCondition(t *testing.T, status map[string]interface{}, conditionType appdbv1.AppDBConditionType) map[string]interface{} {
+	conditions, _ := status["conditions"].([]interface{})
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok == true && condition["type"] == string(conditionType) {
+			return condition
+		}
+	}
+	t.Fatalf("Condition %s not found in status: %v", conditionType, status)
+	return nil
+}
+
+func TestSyncParent(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       interface{}
+		err          error
+		wantErr      bool
+		wantSynced   appdbv1.ConditionStatus
+		wantReason   syncerr.Reason
+		wantMessage  string
+		wantStatus   string
+		wantChildren int
+		wantEvent    bool
+	}{
+		{
+			name:         "success",
+			result:       &testSyncResponse{Status: testStatus{Provisioning: "COMPLETE"}, Children: []interface{}{map[string]interface{}{"apiVersion": "v1", "kind": "Secret", "metadata": map[string]interface{}{"name": "new"}}}},
+			wantSynced:   appdbv1.ConditionTrue,
+			wantStatus:   "COMPLETE",
+			wantChildren: 1,
+		},
+		{
+			name:    "transient error is retried",
+			err:     syncerr.Transientf("API unavailable"),
+			wantErr: true,
+		},
+		{
+			name:    "unclassified error is retried",
+			err:     errors.New("connection refused"),
+			wantErr: true,
+		},
+		{
+			name:         "permanent error keeps status and observed children",
+			err:          syncerr.Permanentf("driver disabled"),
+			wantSynced:   appdbv1.ConditionFalse,
+			wantReason:   syncerr.ReasonPermanent,
+			wantMessage:  "driver disabled",
+			wantStatus:   "PENDING",
+			wantChildren: 2,
+			wantEvent:    true,
+		},
+		{
+			name:         "invalid spec error keeps status and observed children",
+			err:          syncerr.InvalidSpecf("missing spec.dbName"),
+			wantSynced:   appdbv1.ConditionFalse,
+			wantReason:   syncerr.ReasonInvalidSpec,
+			wantMessage:  "missing spec.dbName",
+			wantStatus:   "PENDING",
+			wantChildren: 2,
+			wantEvent:    true,
+		},
+		{
+			name:         "nil result without error is a permanent error",
+			wantSynced:   appdbv1.ConditionFalse,
+			wantReason:   syncerr.ReasonPermanent,
+			wantMessage:  "returned no status",
+			wantStatus:   "PENDING",
+			wantChildren: 2,
+			wantEvent:    true,
+		},
+		{
+			name:         "nil result with permanent error",
+			result:       nil,
+			err:          syncerr.Permanentf("failed"),
+			wantSynced:   appdbv1.ConditionFalse,
+			wantReason:   syncerr.ReasonPermanent,
+			wantMessage:  "failed",
+			wantStatus:   "PENDING",
+			wantChildren: 2,
+			wantEvent:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeRecorder := record.NewFakeRecorder(10)
+			req := newTestRequest()
+
+			resp, err := SyncParent(context.Background(), req, events.NewRecorderFor(fakeRecorder), func(ctx context.Context) (interface{}, error) {
+				return tc.result, tc.err
+			})
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got response: %v", resp)
+				}
+				if resp != nil {
+					t.Errorf("Expected nil response with error, got: %v", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if resp.Status["provisioning"] != tc.wantStatus {
+				t.Errorf("Expected provisioning %q, got: %v", tc.wantStatus, resp.Status["provisioning"])
+			}
+			if len(resp.Children) != tc.wantChildren {
+				t.Errorf("Expected %d children, got: %d", tc.wantChildren, len(resp.Children))
+			}
+
+			synced := findCondition(t, resp.Status, appdbv1.ConditionTypeSynced)
+			if synced["status"] != string(tc.wantSynced) {
+				t.Errorf("Expected Synced %s, got: %v", tc.wantSynced, synced["status"])
+			}
+			if tc.wantReason != "" && synced["reason"] != string(tc.wantReason) {
+				t.Errorf("Expected Synced reason %s, got: %v", tc.wantReason, synced["reason"])
+			}
+			if message, _ := synced["message"].(string); strings.Contains(message, tc.wantMessage) == false {
+				t.Errorf("Expected Synced message to contain %q, got: %q", tc.wantMessage, message)
+			}
+
+			if tc.wantSynced == appdbv1.ConditionFalse {
+				// The other conditions of the parent status are unchanged.
+				findCondition(t, resp.Status, "DBCreateComplete")
+			}
+
+			gotEvent := len(fakeRecorder.Events) > 0
+			if gotEvent != tc.wantEvent {
+				t.Errorf("Expected event %t, got: %t", tc.wantEvent, gotEvent)
+			}
+
+			// The status of the parent in the request is not modified.
+			conditions, _ := req.Parent.Object["status"].(map[string]interface{})["conditions"].([]interface{})
+			if len(conditions) != 1 {
+				t.Errorf("Parent status was modified: %v", req.Parent.Object["status"])
+			}
+		})
+	}
+}
+
+func TestSyncParentWithoutStatus(t *testing.T) {
+	req := newTestRequest()
+	delete(req.Parent.Object, "status")
+
+	resp, err := SyncParent(context.Background(), req, nil, func(ctx context.Context) (interface{}, error) {
+		return nil, syncerr.InvalidSpecf("invalid")
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	synced := findCondition(t, resp.Status, appdbv1.ConditionTypeSynced)
+	if synced["reason"] != string(syncerr.ReasonInvalidSpec) {
+		t.Errorf("Expected Synced reason %s, got: %v", syncerr.ReasonInvalidSpec, synced["reason"])
+	}
+}
+
+func TestSetSyncedCondition(t *testing.T) {
+	conditions := []appdbv1.AppDBCondition{
+		{Type: appdbv1.ConditionTypeAppDBReady, Status: appdbv1.ConditionTrue},
+	}
+
+	conditions = SetSyncedCondition(conditions, syncerr.Permanentf("failed"))
+	if len(conditions) != 2 {
+		t.Fatalf("Expected 2 conditions, got: %d", len(conditions))
+	}
+	failedAt := conditions[1].LastTransitionTime
+
+	conditions = SetSyncedCondition(conditions, syncerr.Permanentf("failed again"))
+	if len(conditions) != 2 || conditions[1].Status != appdbv1.ConditionFalse || conditions[1].Message != "failed again" {
+		t.Fatalf("Unexpected conditions: %+v", conditions)
+	}
+	if conditions[1].LastTransitionTime != failedAt {
+		t.Errorf("Expected unchanged LastTransitionTime without a status change")
+	}
+
+	conditions = SetSyncedCondition(conditions, nil)
+	if conditions[1].Status != appdbv1.ConditionTrue || conditions[1].Reason != "" || conditions[1].Message != "" {
+		t.Errorf("Expected Synced True without reason and message, got: %+v", conditions[1])
+	}
+	if conditions[0].Type != appdbv1.ConditionTypeAppDBReady || conditions[0].Status != appdbv1.ConditionTrue {
+		t.Errorf("Expected other conditions unchanged, got: %+v", conditions[0])
+	}
+}
+
+func TestSyncParentKeepsSyncedTransitionTime(t *testing.T) {
+	req := newTestRequest()
+	status := req.Parent.Object["status"].(map[string]interface{})
+	status["conditions"] = append(status["conditions"].([]interface{}), map[string]interface{}{
+		"type":               "Synced",
+		"status":             "True",
+		"lastTransitionTime": "2018-01-01T00:00:00Z",
+	})
+
+	resp, err := SyncParent(context.Background(), req, nil, func(ctx context.Context) (interface{}, error) {
+		// The status of the controller does not include the Synced condition of the parent.
+		return &testSyncResponse{Status: testStatus{Provisioning: "COMPLETE"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	synced := findCondition(t, resp.Status, appdbv1.ConditionTypeSynced)
+	if synced["lastTransitionTime"] != "2018-01-01T00:00:00Z" {
+		t.Errorf("Expected unchanged lastTransitionTime, got: %v", synced["lastTransitionTime"])
+	}
+}
+
+func TestSyncParentSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := tracing.InitWithExporter("appdb-operator-test", exporter)
+	defer shutdown(context.Background())
+
+	req := newTestRequest()
+	_, err := SyncParent(context.Background(), req, nil, func(ctx context.Context) (interface{}, error) {
+		_, span := tracing.Start(ctx, "terraform apply")
+		tracing.End(span, nil)
+		return nil, syncerr.Transientf("AppDBInstance not ready")
+	})
+	if err == nil {
+		t.Fatalf("Expected transient error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got: %d", len(spans))
+	}
+	child, sync := spans[0], spans[1]
+
+	if sync.Name != "sync AppDB" || child.Name != "terraform apply" {
+		t.Fatalf("Unexpected spans: %s, %s", sync.Name, child.Name)
+	}
+	if child.Parent.SpanID() != sync.SpanContext.SpanID() {
+		t.Errorf("Expected span of the sync func to be a child of the sync span")
+	}
+	if sync.Status.Code != codes.Error || strings.Contains(sync.Status.Description, "AppDBInstance not ready") == false {
+		t.Errorf("Expected error status on sync span, got: %v", sync.Status)
+	}
+
+	wantAttrs := map[attribute.Key]string{
+		"k8s.kind":           "AppDB",
+		"k8s.namespace.name": "default",
+		"k8s.object.name":    "db1",
+		"appdb.request_id":   "test",
+	}
+	for _, a := range sync.Attributes {
+		if want, ok := wantAttrs[a.Key]; ok == true {
+			if a.Value.AsString() != want {
+				t.Errorf("Expected attribute %s=%s, got: %s", a.Key, want, a.Value.AsString())
+			}
+			delete(wantAttrs, a.Key)
+		}
+	}
+	if len(wantAttrs) != 0 {
+		t.Errorf("Missing attributes on sync span: %v", wantAttrs)
+	}
+}
+
+// syncMetrics returns the number of observed and failed syncs of the kind in the default registry.
+func syncMetrics(t *testing.T, kind string) (uint64, float64) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var syncs uint64
+	var errs float64
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			if len(m.GetLabel()) != 1 || m.GetLabel()[0].GetValue() != kind {
+				continue
+			}
+			switch f.GetName() {
+			case "appdb_sync_duration_seconds":
+				syncs = m.GetHistogram().GetSampleCount()
+			case "appdb_sync_errors_total":
+				errs = m.GetCounter().GetValue()
+			}
+		}
+	}
+	return syncs, errs
+}
+
+func TestSyncParentMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantSyncs uint64
+		wantErrs  float64
+	}{
+		{"success", nil, 1, 0},
+		{"transient error", errors.New("API server unavailable"), 2, 1},
+		{"permanent error", syncerr.Permanentf("failed"), 3, 2},
+	}
+
+	for _, tc := range tests {
+		req := newTestRequest()
+		// A kind of its own so that the counts are not shared with the other tests.
+		req.Parent.SetKind("MetricsTest")
+
+		SyncParent(context.Background(), req, nil, func(ctx context.Context) (interface{}, error) {
+			if tc.err != nil {
+				return nil, tc.err
+			}
+			return testSyncResponse{Children: []interface{}{}}, nil
+		})
+
+		syncs, errs := syncMetrics(t, "MetricsTest")
+		if syncs != tc.wantSyncs || errs != tc.wantErrs {
+			t.Errorf("%s: expected %d syncs and %v errors, got: %d and %v", tc.name, tc.wantSyncs, tc.wantErrs, syncs, errs)
+		}
+	}
+}
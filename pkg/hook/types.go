@@ -0,0 +1,75 @@
+// Package hook is the metacontroller sync hook shared by the controllers, the HTTP handler of the webhook mode and the request and response types used by both controller modes.
+package hook
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// SyncRequest is the parent and its observed children, in the format of the metacontroller sync hook request.
+type SyncRequest struct {
+	Parent   *unstructured.Unstructured                       `json:"parent"`
+	Children map[string]map[string]*unstructured.Unstructured `json:"children"`
+	// Finalizing is true for the requests of the finalize hook, sent after the parent was deleted.
+	Finalizing bool `json:"finalizing,omitempty"`
+	// RequestID correlates the log entries of the sync.
+	RequestID string `json:"-"`
+}
+
+// Decode converts the request to the sync request type of the controller.
+func (r *SyncRequest) Decode(out interface{}) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// SyncResponse is the desired status and children of the parent, in the format of the metacontroller sync hook response.
+type SyncResponse struct {
+	Status   map[string]interface{}       `json:"status"`
+	Children []*unstructured.Unstructured `json:"children"`
+	// Finalized is set by the finalize hook when the parent can be deleted.
+	Finalized bool `json:"finalized,omitempty"`
+}
+
+// NewSyncResponse converts the sync response of the controller.
+func NewSyncResponse(in interface{}) (*SyncResponse, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Status    json.RawMessage              `json:"status"`
+		Children  []*unstructured.Unstructured `json:"children"`
+		Finalized bool                         `json:"finalized"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	resp := &SyncResponse{
+		Status:    make(map[string]interface{}, 0),
+		Children:  raw.Children,
+		Finalized: raw.Finalized,
+	}
+	// Integers are decoded as int64 like the objects from the informer cache, so that an unchanged status compares equal.
+	if len(raw.Status) > 0 && string(raw.Status) != "null" {
+		if err := utiljson.Unmarshal(raw.Status, &resp.Status); err != nil {
+			return nil, err
+		}
+	}
+	if resp.Children == nil {
+		resp.Children = make([]*unstructured.Unstructured, 0)
+	}
+
+	return resp, nil
+}
+
+// SyncFunc computes the desired status and children of the parent.
+// Errors are retried with backoff, the status and children are left unchanged.
+type SyncFunc func(ctx context.Context, request *SyncRequest) (*SyncResponse, error)
@@ -0,0 +1,290 @@
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	DEFAULT_RESYNC_PERIOD = 30 * time.Second
+)
+
+var (
+	AppDBResource              = schema.GroupVersionResource{Group: "ctl.isla.solutions", Version: "v1", Resource: "appdbs"}
+	AppDBInstanceResource      = schema.GroupVersionResource{Group: "ctl.isla.solutions", Version: "v1", Resource: "appdbinstances"}
+	AppDBInstanceClassResource = schema.GroupVersionResource{Group: "ctl.isla.solutions", Version: "v1", Resource: "appdbinstanceclasses"}
+	TerraformApplyResource     = schema.GroupVersionResource{Group: "ctl.isla.solutions", Version: "v1", Resource: "terraformapplys"}
+)
+
+// Client reads the ctl.isla.solutions custom resources from a shared informer cache and writes them with the dynamic client.
+type Client struct {
+	dynamic   dynamic.Interface
+	factory   dynamicinformer.DynamicSharedInformerFactory
+	resources []schema.GroupVersionResource
+}
+
+// NewClient creates a new Client from the rest config, the informers for the given resources are registered but not started.
+func NewClient(restConfig *rest.Config, resources ...schema.GroupVersionResource) (*Client, error) {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientForInterface(dynamicClient, DEFAULT_RESYNC_PERIOD, resources...), nil
+}
+
+// NewClientForInterface creates a new Client from an existing dynamic client, for example, the fake dynamic client.
+func NewClientForInterface(dynamicClient dynamic.Interface, resync time.Duration, resources ...schema.GroupVersionResource) *Client {
+	c := &Client{
+		dynamic:   dynamicClient,
+		factory:   dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, resync),
+		resources: resources,
+	}
+
+	for _, gvr := range resources {
+		// Registers the informer with the factory.
+		c.factory.ForResource(gvr)
+	}
+
+	return c
+}
+
+// Start starts the informers and waits for the caches to sync.
+func (c *Client) Start(stopCh <-chan struct{}) error {
+	c.factory.Start(stopCh)
+
+	for gvr, synced := range c.factory.WaitForCacheSync(stopCh) {
+		if synced == false {
+			return fmt.Errorf("Failed to sync informer cache for %s", gvr.String())
+		}
+	}
+
+	log.Printf("[INFO] Informer caches synced for %d resources", len(c.resources))
+
+	return nil
+}
+
+// GetAppDB returns the AppDB from the informer cache.
+func (c *Client) GetAppDB(namespace, name string) (appdbv1.AppDB, error) {
+	var appdb appdbv1.AppDB
+	err := c.get(AppDBResource, namespace, name, &appdb)
+	return appdb, err
+}
+
+// ListAppDBs returns the AppDBs in all namespaces from the informer cache.
+func (c *Client) ListAppDBs() ([]appdbv1.AppDB, error) {
+	appdbs := make([]appdbv1.AppDB, 0)
+
+	objs, err := c.factory.ForResource(AppDBResource).Lister().List(labels.Everything())
+	if err != nil {
+		return appdbs, err
+	}
+
+	for _, obj := range objs {
+		var appdb appdbv1.AppDB
+		if err := fromObject(obj, &appdb); err != nil {
+			return appdbs, err
+		}
+		appdbs = append(appdbs, appdb)
+	}
+
+	return appdbs, nil
+}
+
+// GetAppDBInstance returns the AppDBInstance from the informer cache.
+func (c *Client) GetAppDBInstance(namespace, name string) (appdbv1.AppDBInstance, error) {
+	var appdbi appdbv1.AppDBInstance
+	err := c.get(AppDBInstanceResource, namespace, name, &appdbi)
+	return appdbi, err
+}
+
+// ListAppDBInstances returns the AppDBInstances in all namespaces matching the selector from the informer cache.
+func (c *Client) ListAppDBInstances(selector labels.Selector) ([]appdbv1.AppDBInstance, error) {
+	instances := make([]appdbv1.AppDBInstance, 0)
+
+	objs, err := c.factory.ForResource(AppDBInstanceResource).Lister().List(selector)
+	if err != nil {
+		return instances, err
+	}
+
+	for _, obj := range objs {
+		var appdbi appdbv1.AppDBInstance
+		if err := fromObject(obj, &appdbi); err != nil {
+			return instances, err
+		}
+		instances = append(instances, appdbi)
+	}
+
+	return instances, nil
+}
+
+// CreateAppDBInstance creates a new AppDBInstance.
+func (c *Client) CreateAppDBInstance(appdbi appdbv1.AppDBInstance) error {
+	obj, err := toUnstructured(appdbi)
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamic.Resource(AppDBInstanceResource).Namespace(appdbi.GetNamespace()).Create(obj, metav1.CreateOptions{})
+	return err
+}
+
+// FetchAppDBInstance returns the AppDBInstance from the API server instead of the informer cache, for updates that must not be based on a stale object.
+func (c *Client) FetchAppDBInstance(namespace, name string) (appdbv1.AppDBInstance, error) {
+	var appdbi appdbv1.AppDBInstance
+	obj, err := c.dynamic.Resource(AppDBInstanceResource).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return appdbi, err
+	}
+	err = fromObject(obj, &appdbi)
+	return appdbi, err
+}
+
+// UpdateAppDBInstance updates the AppDBInstance, the update fails with a conflict if the resourceVersion of the object is not the current one.
+func (c *Client) UpdateAppDBInstance(appdbi appdbv1.AppDBInstance) error {
+	obj, err := toUnstructured(appdbi)
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamic.Resource(AppDBInstanceResource).Namespace(appdbi.GetNamespace()).Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+// GetAppDBInstanceClass returns the cluster-scoped AppDBInstanceClass from the informer cache.
+func (c *Client) GetAppDBInstanceClass(name string) (appdbv1.AppDBInstanceClass, error) {
+	var class appdbv1.AppDBInstanceClass
+	err := c.get(AppDBInstanceClassResource, "", name, &class)
+	return class, err
+}
+
+// UpdateTerraformApply replaces the spec and annotations of an existing TerraformApply, keeping the rest of the current object.
+func (c *Client) UpdateTerraformApply(current tfv1.Terraform, desired tfv1.Terraform) error {
+	current.Spec = desired.Spec
+	if current.Annotations == nil {
+		current.Annotations = make(map[string]string, 0)
+	}
+	for k, v := range desired.Annotations {
+		current.Annotations[k] = v
+	}
+
+	obj, err := toUnstructured(current)
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamic.Resource(TerraformApplyResource).Namespace(current.GetNamespace()).Update(obj, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Client) get(gvr schema.GroupVersionResource, namespace, name string, out interface{}) error {
+	obj, err := c.Get(gvr, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	return fromObject(obj, out)
+}
+
+func fromObject(obj runtime.Object, out interface{}) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if ok == false {
+		return fmt.Errorf("Unexpected object type in informer cache: %T", obj)
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), out)
+}
+
+func toUnstructured(in interface{}) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Informer returns the shared informer for the resource, registering it with the factory if needed.
+// Informers registered after Start are not started.
+func (c *Client) Informer(gvr schema.GroupVersionResource) cache.SharedIndexInformer {
+	found := false
+	for _, r := range c.resources {
+		if r == gvr {
+			found = true
+			break
+		}
+	}
+	if found == false {
+		c.resources = append(c.resources, gvr)
+	}
+	return c.factory.ForResource(gvr).Informer()
+}
+
+// Get returns the object of the resource from the informer cache, the object is shared with the cache and must be copied before it is modified.
+func (c *Client) Get(gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	var obj runtime.Object
+	var err error
+
+	lister := c.factory.ForResource(gvr).Lister()
+	if namespace == "" {
+		obj, err = lister.Get(name)
+	} else {
+		obj, err = lister.ByNamespace(namespace).Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if ok == false {
+		return nil, fmt.Errorf("Unexpected object type in informer cache: %T", obj)
+	}
+	return u, nil
+}
+
+// List returns the objects of the resource in the namespace from the informer cache, the objects are shared with the cache and must be copied before they are modified.
+func (c *Client) List(gvr schema.GroupVersionResource, namespace string) ([]*unstructured.Unstructured, error) {
+	items := make([]*unstructured.Unstructured, 0)
+
+	objs, err := c.factory.ForResource(gvr).Lister().ByNamespace(namespace).List(labels.Everything())
+	if err != nil {
+		return items, err
+	}
+
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if ok == false {
+			return items, fmt.Errorf("Unexpected object type in informer cache: %T", obj)
+		}
+		items = append(items, u)
+	}
+
+	return items, nil
+}
+
+// Resource returns the dynamic client for the resource.
+func (c *Client) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c.dynamic.Resource(gvr)
+}
+
+// HasSynced returns true when the informer caches for all resources have synced.
+func (c *Client) HasSynced() bool {
+	for _, gvr := range c.resources {
+		if c.factory.ForResource(gvr).Informer().HasSynced() == false {
+			return false
+		}
+	}
+	return true
+}
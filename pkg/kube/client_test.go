@@ -0,0 +1,161 @@
+package kube
+
+import (
+	"testing"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newObject(kind, namespace, name string, labels map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("ctl.isla.solutions/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	return obj
+}
+
+func newTestClient(t *testing.T, objects ...runtime.Object) (*Client, *fake.FakeDynamicClient) {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	c := NewClientForInterface(dynamicClient, 0, AppDBResource, AppDBInstanceResource, AppDBInstanceClassResource)
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+
+	if err := c.Start(stopCh); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	if c.HasSynced() == false {
+		t.Fatalf("Expected informer caches to be synced after Start")
+	}
+
+	return c, dynamicClient
+}
+
+func TestGetAppDB(t *testing.T) {
+	c, _ := newTestClient(t,
+		newObject("AppDB", "default", "db1", nil, map[string]interface{}{"appDBInstance": "inst1", "dbName": "app"}),
+	)
+
+	appdb, err := c.GetAppDB("default", "db1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if appdb.Spec.AppDBInstance != "inst1" || appdb.Spec.DBName != "app" {
+		t.Errorf("Unexpected spec: %+v", appdb.Spec)
+	}
+
+	_, err = c.GetAppDB("other", "db1")
+	if errors.IsNotFound(err) == false {
+		t.Errorf("Expected NotFound for AppDB in other namespace, got: %v", err)
+	}
+}
+
+func TestGetAppDBInstanceClass(t *testing.T) {
+	c, _ := newTestClient(t,
+		newObject("AppDBInstanceClass", "", "small", nil, map[string]interface{}{"placement": map[string]interface{}{"maxDatabasesPerInstance": int64(5)}}),
+	)
+
+	class, err := c.GetAppDBInstanceClass("small")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if class.Spec.Placement.MaxDatabasesPerInstance != 5 {
+		t.Errorf("Expected maxDatabasesPerInstance 5, got: %d", class.Spec.Placement.MaxDatabasesPerInstance)
+	}
+}
+
+func TestListAppDBs(t *testing.T) {
+	c, _ := newTestClient(t,
+		newObject("AppDB", "default", "db1", nil, nil),
+		newObject("AppDB", "default", "db2", nil, nil),
+		newObject("AppDB", "team-a", "db3", nil, nil),
+	)
+
+	appdbs, err := c.ListAppDBs()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(appdbs) != 3 {
+		t.Errorf("Expected 3 AppDBs across namespaces, got: %d", len(appdbs))
+	}
+
+	items, err := c.List(AppDBResource, "default")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("Expected 2 AppDBs in namespace default, got: %d", len(items))
+	}
+}
+
+func TestListAppDBInstances(t *testing.T) {
+	c, _ := newTestClient(t,
+		newObject("AppDBInstance", "default", "inst1", map[string]string{"tier": "small"}, nil),
+		newObject("AppDBInstance", "default", "inst2", map[string]string{"tier": "large"}, nil),
+	)
+
+	tests := []struct {
+		selector string
+		want     int
+	}{
+		{"", 2},
+		{"tier=small", 1},
+		{"tier=medium", 0},
+	}
+
+	for _, tc := range tests {
+		selector, err := labels.Parse(tc.selector)
+		if err != nil {
+			t.Fatalf("Failed to parse selector %q: %v", tc.selector, err)
+		}
+		instances, err := c.ListAppDBInstances(selector)
+		if err != nil {
+			t.Fatalf("Unexpected error for selector %q: %v", tc.selector, err)
+		}
+		if len(instances) != tc.want {
+			t.Errorf("Selector %q: expected %d instances, got: %d", tc.selector, tc.want, len(instances))
+		}
+	}
+}
+
+func TestCreateAppDBInstance(t *testing.T) {
+	c, dynamicClient := newTestClient(t)
+
+	appdbi := appdbv1.AppDBInstance{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "ctl.isla.solutions/v1",
+			Kind:       "AppDBInstance",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "inst1",
+		},
+	}
+
+	if err := c.CreateAppDBInstance(appdbi); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	obj, err := dynamicClient.Resource(AppDBInstanceResource).Namespace("default").Get("inst1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected created AppDBInstance, got: %v", err)
+	}
+	if obj.GetKind() != "AppDBInstance" {
+		t.Errorf("Unexpected kind: %s", obj.GetKind())
+	}
+
+	if err := c.CreateAppDBInstance(appdbi); errors.IsAlreadyExists(err) == false {
+		t.Errorf("Expected AlreadyExists on second create, got: %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Format is the output format of the log entries.
+type Format string
+
+const (
+	// FormatJSON writes one JSON object per line, for log pipelines.
+	FormatJSON Format = "json"
+	// FormatText writes the conventional [LEVEL] prefixed lines followed by the fields, for local development.
+	FormatText Format = "text"
+)
+
+// Field keys shared by both operators so logs can be queried per resource.
+const (
+	KeyKind      = "kind"
+	KeyNamespace = "namespace"
+	KeyName      = "name"
+	KeyCondition = "condition"
+	KeyChild     = "child"
+	KeyRequestID = "requestID"
+	KeyTraceID   = "traceID"
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// ParseLevel parses one of debug, info, warn or error, case insensitive.
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToUpper(s)
+	if s == "WARNING" {
+		s = "WARN"
+	}
+	for level, name := range levelNames {
+		if name == s {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("Unsupported log level: %s, must be one of: debug, info, warn, error", s)
+}
+
+var (
+	mu        sync.Mutex
+	out       io.Writer = os.Stderr
+	minLevel            = LevelInfo
+	logFormat           = FormatJSON
+	httpDebug           = false
+)
+
+// Configure sets the minimum level, format and output of all loggers.
+// The standard library logger is redirected so that log.Printf("[LEVEL] ...") calls are written in the same format.
+func Configure(level Level, format Format, w io.Writer) error {
+	if format != FormatJSON && format != FormatText {
+		return fmt.Errorf("Unsupported log format: %s, must be one of: %s, %s", format, FormatJSON, FormatText)
+	}
+
+	mu.Lock()
+	minLevel = level
+	logFormat = format
+	out = w
+	mu.Unlock()
+
+	log.SetFlags(0)
+	log.SetOutput(stdLogWriter{})
+
+	return nil
+}
+
+// Config is the logging config, the settings are read by the operator config.
+type Config struct {
+	Level     string
+	Format    string
+	HTTPDebug bool
+}
+
+// LoadAndValidate configures logging from the config, the defaults are info and json.
+func (c *Config) LoadAndValidate() error {
+	level := LevelInfo
+	if c.Level != "" {
+		var err error
+		if level, err = ParseLevel(c.Level); err != nil {
+			return err
+		}
+	}
+
+	format := FormatJSON
+	if c.Format != "" {
+		format = Format(strings.ToLower(c.Format))
+	}
+
+	mu.Lock()
+	httpDebug = c.HTTPDebug
+	mu.Unlock()
+
+	return Configure(level, format, os.Stderr)
+}
+
+// HTTPDebug returns true if the sync requests and responses are logged.
+func HTTPDebug() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return httpDebug
+}
+
+// Fields are the structured key value pairs of a log entry.
+type Fields map[string]interface{}
+
+// Logger writes log entries with a fixed set of fields, loggers are immutable and safe for concurrent use.
+type Logger struct {
+	fields Fields
+}
+
+// New returns a Logger without fields.
+func New() *Logger {
+	return &Logger{fields: Fields{}}
+}
+
+// With returns a copy of the logger with the field added.
+func (l *Logger) With(key string, value interface{}) *Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+// WithFields returns a copy of the logger with the fields added.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	newFields := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		newFields[k] = v
+	}
+	for k, v := range fields {
+		newFields[k] = v
+	}
+	return &Logger{fields: newFields}
+}
+
+// ForObject returns a copy of the logger with the kind, namespace and name of a resource.
+func (l *Logger) ForObject(kind, namespace, name string) *Logger {
+	return l.WithFields(Fields{
+		KeyKind:      kind,
+		KeyNamespace: namespace,
+		KeyName:      name,
+	})
+}
+
+// WithChild returns a copy of the logger with the child resource formatted as <kind>/<name>.
+func (l *Logger) WithChild(kind, name string) *Logger {
+	return l.With(KeyChild, fmt.Sprintf("%s/%s", kind, name))
+}
+
+func (l *Logger) Debugf(msgfmt string, fmtargs ...interface{}) {
+	l.Log(LevelDebug, msgfmt, fmtargs...)
+}
+
+func (l *Logger) Infof(msgfmt string, fmtargs ...interface{}) {
+	l.Log(LevelInfo, msgfmt, fmtargs...)
+}
+
+func (l *Logger) Warnf(msgfmt string, fmtargs ...interface{}) {
+	l.Log(LevelWarn, msgfmt, fmtargs...)
+}
+
+func (l *Logger) Errorf(msgfmt string, fmtargs ...interface{}) {
+	l.Log(LevelError, msgfmt, fmtargs...)
+}
+
+// Log writes the entry if the level is at or above the configured minimum level.
+func (l *Logger) Log(level Level, msgfmt string, fmtargs ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if level < minLevel {
+		return
+	}
+
+	msg := msgfmt
+	if len(fmtargs) > 0 {
+		msg = fmt.Sprintf(msgfmt, fmtargs...)
+	}
+
+	var line []byte
+	switch logFormat {
+	case FormatText:
+		line = formatText(level, msg, l.fields)
+	default:
+		line = formatJSON(level, msg, l.fields)
+	}
+
+	out.Write(append(line, '\n'))
+}
+
+func formatJSON(level Level, msg string, fields Fields) []byte {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		entry[k] = v
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = strings.ToLower(level.String())
+	entry["msg"] = msg
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return formatText(level, fmt.Sprintf("%s (failed to encode log fields: %v)", msg, err), nil)
+	}
+	return data
+}
+
+func formatText(level Level, msg string, fields Fields) []byte {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s [%s] %s", time.Now().Format("2006/01/02 15:04:05"), level, msg)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return []byte(b.String())
+}
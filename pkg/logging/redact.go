@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"encoding/json"
+)
+
+// Redacted replaces the values of Secret data in HTTP_DEBUG dumps.
+const Redacted = "REDACTED"
+
+// sensitiveTFOutputs are the sensitive output variables of the Terraform configs, reported in status.outputs of the TerraformApply and TerraformPlan.
+var sensitiveTFOutputs = map[string]bool{
+	"user_passwords":       true,
+	"vault_admin_password": true,
+	"client_key":           true,
+	"admin_pass":           true,
+	"proxy_sa_key":         true,
+}
+
+// RedactSecrets returns the JSON document with the data and stringData values of every embedded Secret
+// and the sensitive output values of every embedded TerraformApply and TerraformPlan replaced.
+// Documents that cannot be parsed are replaced entirely, because they may contain secrets.
+func RedactSecrets(data []byte) []byte {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(Redacted)
+	}
+
+	redacted, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return []byte(Redacted)
+	}
+	return redacted
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		isSecret := val["kind"] == "Secret"
+		isTerraform := val["kind"] == "TerraformApply" || val["kind"] == "TerraformPlan"
+		for k, child := range val {
+			if isSecret && (k == "data" || k == "stringData") {
+				val[k] = redactSecretData(child)
+				continue
+			}
+			if isTerraform && k == "status" {
+				redactTFOutputs(child)
+			}
+			val[k] = redactValue(child)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = redactValue(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func redactSecretData(v interface{}) interface{} {
+	data, ok := v.(map[string]interface{})
+	if ok == false {
+		return Redacted
+	}
+	for k := range data {
+		data[k] = Redacted
+	}
+	return data
+}
+
+func redactTFOutputs(v interface{}) {
+	status, ok := v.(map[string]interface{})
+	if ok == false {
+		return
+	}
+	outputs, ok := status["outputs"].(map[string]interface{})
+	if ok == false {
+		return
+	}
+	for k, output := range outputs {
+		if sensitiveTFOutputs[k] == false {
+			continue
+		}
+		if outputVar, ok := output.(map[string]interface{}); ok == true {
+			outputVar["value"] = Redacted
+		} else {
+			outputs[k] = Redacted
+		}
+	}
+}
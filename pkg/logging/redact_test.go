@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		redacted []string
+		kept     []string
+	}{
+		{
+			"secret",
+			`{"children":[{"kind":"Secret","data":{"password":"c2VjcmV0"},"stringData":{"user":"admin"}}]}`,
+			[]string{"c2VjcmV0", "admin"},
+			[]string{`"password"`},
+		},
+		{
+			"terraform apply outputs",
+			`{"kind":"TerraformApply","status":{"outputs":{"user_passwords":{"type":"string","value":"p1,p2"},"proxy_sa_key":{"value":"a2V5"},"vault_admin_password":{"value":"vp"},"admin_pass":{"value":"ap"},"client_key":{"value":"ck"},"port":{"value":"3306"}}}}`,
+			[]string{"p1,p2", "a2V5", `"vp"`, `"ap"`, `"ck"`},
+			[]string{"3306", `"user_passwords"`},
+		},
+		{
+			"terraform plan in children",
+			`{"children":{"TerraformPlan.ctl.isla.solutions/v1":{"db":{"kind":"TerraformPlan","status":{"outputs":{"admin_pass":{"value":"ap"}}}}}}}`,
+			[]string{`"ap"`},
+			nil,
+		},
+		{
+			"outputs of other kinds",
+			`{"kind":"AppDB","status":{"outputs":{"user_passwords":{"value":"p1"}}}}`,
+			nil,
+			[]string{"p1"},
+		},
+	}
+
+	for _, tc := range tests {
+		out := string(RedactSecrets([]byte(tc.in)))
+		for _, s := range tc.redacted {
+			if strings.Contains(out, s) {
+				t.Errorf("%s: expected %s to be redacted, got: %s", tc.name, s, out)
+			}
+		}
+		for _, s := range tc.kept {
+			if strings.Contains(out, s) == false {
+				t.Errorf("%s: expected %s to be kept, got: %s", tc.name, s, out)
+			}
+		}
+	}
+
+	if out := string(RedactSecrets([]byte("not json"))); out != Redacted {
+		t.Errorf("Expected unparsable document to be redacted, got: %s", out)
+	}
+}
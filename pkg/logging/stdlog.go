@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"strings"
+)
+
+// stdLogWriter parses the [LEVEL] prefix used by the log.Printf calls and writes them as structured entries.
+type stdLogWriter struct{}
+
+func (w stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	level := LevelInfo
+
+	if strings.HasPrefix(msg, "[") {
+		if end := strings.Index(msg, "]"); end > 0 {
+			if l, err := ParseLevel(msg[1:end]); err == nil {
+				level = l
+				msg = strings.TrimLeft(msg[end+1:], " ")
+			}
+		}
+	}
+
+	New().Log(level, "%s", msg)
+
+	return len(p), nil
+}
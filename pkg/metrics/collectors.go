@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"log"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	appdbConditionDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "condition"),
+		"Status of each AppDB condition, 1 for the current status of the condition.",
+		[]string{"namespace", "appdb", "condition", "status"}, nil,
+	)
+
+	appdbInstanceProvisioningDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "instance", "provisioning"),
+		"Provisioning state of each AppDBInstance, 1 for the current state.",
+		[]string{"namespace", "appdbinstance", "state"}, nil,
+	)
+
+	conditionStatuses = []appdbv1.ConditionStatus{
+		appdbv1.ConditionTrue,
+		appdbv1.ConditionFalse,
+		appdbv1.ConditionUnknown,
+	}
+
+	provisioningStates = []appdbv1.ProvisioningStatus{
+		appdbv1.ProvisioningStatusPending,
+		appdbv1.ProvisioningStatusFailed,
+		appdbv1.ProvisioningStatusComplete,
+	}
+)
+
+// appdbCollector exports the status conditions of the AppDBs in the informer cache when scraped.
+type appdbCollector struct {
+	listAppDBs     func() ([]appdbv1.AppDB, error)
+	conditionTypes []appdbv1.AppDBConditionType
+}
+
+// RegisterAppDBCollector registers a collector for the given condition types of all AppDBs returned by listAppDBs.
+func RegisterAppDBCollector(listAppDBs func() ([]appdbv1.AppDB, error), conditionTypes []appdbv1.AppDBConditionType) {
+	prometheus.MustRegister(&appdbCollector{
+		listAppDBs:     listAppDBs,
+		conditionTypes: conditionTypes,
+	})
+}
+
+func (c *appdbCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- appdbConditionDesc
+}
+
+func (c *appdbCollector) Collect(ch chan<- prometheus.Metric) {
+	appdbs, err := c.listAppDBs()
+	if err != nil {
+		log.Printf("[ERROR] Failed to list AppDBs for metrics: %v", err)
+		return
+	}
+
+	for _, appdb := range appdbs {
+		for _, conditionType := range c.conditionTypes {
+			for _, condition := range appdb.Status.Conditions {
+				if condition.Type != conditionType {
+					continue
+				}
+				for _, status := range conditionStatuses {
+					ch <- prometheus.MustNewConstMetric(appdbConditionDesc, prometheus.GaugeValue, boolValue(condition.Status == status),
+						appdb.GetNamespace(), appdb.GetName(), string(conditionType), string(status))
+				}
+			}
+		}
+	}
+}
+
+// appdbInstanceCollector exports the provisioning state of the AppDBInstances in the informer cache when scraped.
+type appdbInstanceCollector struct {
+	listAppDBInstances func(labels.Selector) ([]appdbv1.AppDBInstance, error)
+}
+
+// RegisterAppDBInstanceCollector registers a collector for the provisioning state of all AppDBInstances returned by listAppDBInstances.
+func RegisterAppDBInstanceCollector(listAppDBInstances func(labels.Selector) ([]appdbv1.AppDBInstance, error)) {
+	prometheus.MustRegister(&appdbInstanceCollector{
+		listAppDBInstances: listAppDBInstances,
+	})
+}
+
+func (c *appdbInstanceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- appdbInstanceProvisioningDesc
+}
+
+func (c *appdbInstanceCollector) Collect(ch chan<- prometheus.Metric) {
+	instances, err := c.listAppDBInstances(labels.Everything())
+	if err != nil {
+		log.Printf("[ERROR] Failed to list AppDBInstances for metrics: %v", err)
+		return
+	}
+
+	for _, appdbi := range instances {
+		for _, state := range provisioningStates {
+			ch <- prometheus.MustNewConstMetric(appdbInstanceProvisioningDesc, prometheus.GaugeValue, boolValue(appdbi.Status.Provisioning == state),
+				appdbi.GetNamespace(), appdbi.GetName(), string(state))
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
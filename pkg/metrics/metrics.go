@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "appdb"
+
+var (
+	syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_duration_seconds",
+		Help:      "Duration of the metacontroller sync webhook by parent kind.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind"})
+
+	syncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_errors_total",
+		Help:      "Number of failed syncs by parent kind.",
+	}, []string{"kind"})
+
+	terraformRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "terraform_retries_total",
+		Help:      "Number of times a failed TerraformApply or TerraformPlan was deleted to be retried, by parent kind.",
+	}, []string{"kind", "parent_kind"})
+
+	loadJobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "load_job_duration_seconds",
+		Help:      "Duration of the SQL load jobs by result.",
+		Buckets:   prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{"result"})
+)
+
+func init() {
+	prometheus.MustRegister(syncDuration, syncErrors, terraformRetries, loadJobDuration)
+}
+
+// Handler returns the http handler for the /metrics endpoint.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveSync records the duration of a sync started at start, and counts it as failed if err is not nil.
+func ObserveSync(kind string, start time.Time, err error) {
+	syncDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncErrors.WithLabelValues(kind).Inc()
+	}
+}
+
+// IncTerraformRetry counts a retry of the TerraformApply or TerraformPlan owned by a parent of the given kind.
+func IncTerraformRetry(kind, parentKind string) {
+	terraformRetries.WithLabelValues(kind, parentKind).Inc()
+}
+
+// ObserveLoadJob records the duration of a finished SQL load job, result is either succeeded or failed.
+func ObserveLoadJob(result string, duration time.Duration) {
+	loadJobDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
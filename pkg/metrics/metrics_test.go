@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// gather returns the metric families of the default registry by name.
+func gather(t *testing.T) map[string]*dto.MetricFamily {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	byName := make(map[string]*dto.MetricFamily, 0)
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+	return byName
+}
+
+// histogramCount returns the sample count of the histogram with the given label value, 0 if it was not observed.
+func histogramCount(t *testing.T, name, labelValue string) uint64 {
+	f, ok := gather(t)[name]
+	if ok == false {
+		return 0
+	}
+	for _, m := range f.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetValue() == labelValue {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestRegistered(t *testing.T) {
+	// The vectors are only gathered once they have a child, observe one of each.
+	ObserveSync("Registered", time.Now(), fmt.Errorf("failed"))
+	IncTerraformRetry("TerraformApply", "Registered")
+	ObserveLoadJob("registered", time.Second)
+
+	families := gather(t)
+	for _, name := range []string{"appdb_sync_duration_seconds", "appdb_sync_errors_total", "appdb_terraform_retries_total", "appdb_load_job_duration_seconds"} {
+		if _, ok := families[name]; ok == false {
+			t.Errorf("Expected %s to be registered", name)
+		}
+	}
+}
+
+func TestObserveSync(t *testing.T) {
+	ObserveSync("AppDB", time.Now(), nil)
+
+	if got := histogramCount(t, "appdb_sync_duration_seconds", "AppDB"); got != 1 {
+		t.Errorf("Expected 1 sync observed, got: %d", got)
+	}
+	if got := testutil.ToFloat64(syncErrors.WithLabelValues("AppDB")); got != 0 {
+		t.Errorf("Expected no sync errors, got: %v", got)
+	}
+
+	ObserveSync("AppDB", time.Now(), fmt.Errorf("failed"))
+
+	if got := histogramCount(t, "appdb_sync_duration_seconds", "AppDB"); got != 2 {
+		t.Errorf("Expected failed syncs to be observed, got: %d", got)
+	}
+	if got := testutil.ToFloat64(syncErrors.WithLabelValues("AppDB")); got != 1 {
+		t.Errorf("Expected 1 sync error, got: %v", got)
+	}
+}
+
+func TestIncTerraformRetry(t *testing.T) {
+	IncTerraformRetry("TerraformPlan", "AppDBInstance")
+	IncTerraformRetry("TerraformPlan", "AppDBInstance")
+
+	if got := testutil.ToFloat64(terraformRetries.WithLabelValues("TerraformPlan", "AppDBInstance")); got != 2 {
+		t.Errorf("Expected 2 retries, got: %v", got)
+	}
+}
+
+func TestObserveLoadJob(t *testing.T) {
+	ObserveLoadJob("succeeded", 30*time.Second)
+	ObserveLoadJob("failed", time.Minute)
+
+	for _, result := range []string{"succeeded", "failed"} {
+		if got := histogramCount(t, "appdb_load_job_duration_seconds", result); got != 1 {
+			t.Errorf("Expected 1 %s load job, got: %d", result, got)
+		}
+	}
+}
+
+func TestCollectors(t *testing.T) {
+	appdbs := []appdbv1.AppDB{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db1"},
+		Status: appdbv1.AppDBOperatorStatus{Conditions: []appdbv1.AppDBCondition{
+			{Type: appdbv1.ConditionTypeAppDBInstanceReady, Status: appdbv1.ConditionTrue},
+		}},
+	}}
+	instances := []appdbv1.AppDBInstance{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "example"},
+		Status:     appdbv1.AppDBInstanceOperatorStatus{Provisioning: appdbv1.ProvisioningStatusComplete},
+	}}
+
+	appdbC := &appdbCollector{
+		listAppDBs:     func() ([]appdbv1.AppDB, error) { return appdbs, nil },
+		conditionTypes: []appdbv1.AppDBConditionType{appdbv1.ConditionTypeAppDBInstanceReady},
+	}
+	instanceC := &appdbInstanceCollector{
+		listAppDBInstances: func(labels.Selector) ([]appdbv1.AppDBInstance, error) { return instances, nil },
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(appdbC, instanceC)
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A series per status and state, only the current one is 1.
+	want := map[string]string{"appdb_condition": string(appdbv1.ConditionTrue), "appdb_instance_provisioning": string(appdbv1.ProvisioningStatusComplete)}
+	for _, f := range families {
+		current, ok := want[f.GetName()]
+		if ok == false {
+			t.Errorf("Unexpected metric: %s", f.GetName())
+			continue
+		}
+		if len(f.GetMetric()) != 3 {
+			t.Errorf("%s: expected 3 series, got: %d", f.GetName(), len(f.GetMetric()))
+		}
+		for _, m := range f.GetMetric() {
+			isCurrent := false
+			for _, l := range m.GetLabel() {
+				if (l.GetName() == "status" || l.GetName() == "state") && l.GetValue() == current {
+					isCurrent = true
+				}
+			}
+			if (m.GetGauge().GetValue() == 1) != isCurrent {
+				t.Errorf("%s: unexpected value %v for %v", f.GetName(), m.GetGauge().GetValue(), m.GetLabel())
+			}
+		}
+		delete(want, f.GetName())
+	}
+	if len(want) != 0 {
+		t.Errorf("Expected metrics not collected: %v", want)
+	}
+}
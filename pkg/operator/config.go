@@ -0,0 +1,429 @@
+// Package operator is the configuration and helpers shared by the AppDB and AppDBInstance controllers.
+package operator
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"github.com/danisla/appdb-operator/pkg/standalone"
+	tfdriverv1 "github.com/danisla/appdb-operator/pkg/tfdriver"
+	vaultv1 "github.com/danisla/appdb-operator/pkg/vault"
+	webhookv1 "github.com/danisla/appdb-operator/pkg/webhook"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Config is the configuration shared by the controllers.
+// Each setting is read from, in order of precedence, a command line flag, an environment variable and the optional config file, the project is also read from the GCE metadata server when available.
+type Config struct {
+	Project                      string
+	ProjectNum                   string
+	ConfigFile                   string
+	Kubeconfig                   string
+	UseMetadataServer            bool
+	RestConfig                   *rest.Config
+	Clientset                    *kubernetes.Clientset
+	KubeClient                   *kubev1.Client
+	CloudSQLProxyImage           string
+	CloudSQLProxyImagePullPolicy corev1.PullPolicy
+	ProxyInjectorPort            string
+	ProxyInjectorTLSCertFile     string
+	ProxyInjectorTLSKeyFile      string
+	ConversionWebhookPort        string
+	ConversionWebhookTLSCertFile string
+	ConversionWebhookTLSKeyFile  string
+	ListenAddr                   string
+	EnableAppDB                  bool
+	EnableAppDBInstance          bool
+	TFDriver                     tfdriverv1.TerraformDriverConfig
+	Vault                        vaultv1.VaultConfig
+	Webhook                      webhookv1.Config
+	Standalone                   standalone.Config
+	Logging                      logging.Config
+
+	flags *flag.FlagSet
+}
+
+// option is a setting of the config, name is the flag name and the key in the config file.
+type option struct {
+	name  string
+	env   string
+	usage string
+	set   setter
+}
+
+// setter parses and sets the value of an option.
+type setter interface {
+	Set(value string) error
+}
+
+// setterFunc is a setter for options with a string flag.
+type setterFunc func(value string) error
+
+func (f setterFunc) Set(value string) error {
+	return f(value)
+}
+
+// boolSetter is a setter for options with a bool flag, the flag can be given without a value.
+type boolSetter struct {
+	field *bool
+}
+
+func (s boolSetter) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s, must be true or false", value)
+	}
+	*s.field = b
+	return nil
+}
+
+func (c *Config) options() []option {
+	return []option{
+		{"project", "PROJECT", "GCP project ID, read from the metadata server when empty", stringSetter(&c.Project)},
+		{"project-num", "PROJECT_NUM", "GCP project number, read from the metadata server when empty", stringSetter(&c.ProjectNum)},
+		{"metadata-server", "USE_METADATA_SERVER", "read the project from the GCE metadata server when it is not set and the metadata server is available", boolSetter{&c.UseMetadataServer}},
+		// KUBECONFIG is read by the kubeconfig loading rules, it may be a list of files.
+		{"kubeconfig", "", "path to a kubeconfig file, used instead of the in-cluster config", stringSetter(&c.Kubeconfig)},
+		{"listen-addr", "LISTEN_ADDR", "address of the sync webhook, health and metrics server", stringSetter(&c.ListenAddr)},
+		{"cloud-sql-proxy-image", "CLOUD_SQL_PROXY_IMAGE", "Cloud SQL proxy image", stringSetter(&c.CloudSQLProxyImage)},
+		{"cloud-sql-proxy-image-pull-policy", "CLOUD_SQL_PROXY_IMAGE_PULL_POLICY", "Cloud SQL proxy image pull policy", setterFunc(func(value string) error {
+			c.CloudSQLProxyImagePullPolicy = corev1.PullPolicy(value)
+			return nil
+		})},
+		{"proxy-injector-port", "PROXY_INJECTOR_PORT", "port of the proxy sidecar injector", stringSetter(&c.ProxyInjectorPort)},
+		{"proxy-injector-tls-cert-file", "PROXY_INJECTOR_TLS_CERT_FILE", "TLS certificate of the proxy sidecar injector, the injector is only enabled when the certificate and key are set", stringSetter(&c.ProxyInjectorTLSCertFile)},
+		{"proxy-injector-tls-key-file", "PROXY_INJECTOR_TLS_KEY_FILE", "TLS key of the proxy sidecar injector", stringSetter(&c.ProxyInjectorTLSKeyFile)},
+		{"conversion-webhook-port", "CONVERSION_WEBHOOK_PORT", "port of the CRD conversion webhook", stringSetter(&c.ConversionWebhookPort)},
+		{"conversion-webhook-tls-cert-file", "CONVERSION_WEBHOOK_TLS_CERT_FILE", "TLS certificate of the CRD conversion webhook, the webhook is only enabled when the certificate and key are set", stringSetter(&c.ConversionWebhookTLSCertFile)},
+		{"conversion-webhook-tls-key-file", "CONVERSION_WEBHOOK_TLS_KEY_FILE", "TLS key of the CRD conversion webhook", stringSetter(&c.ConversionWebhookTLSKeyFile)},
+		{"enable-appdb-controller", "ENABLE_APPDB_CONTROLLER", "run the AppDB controller", boolSetter{&c.EnableAppDB}},
+		{"enable-appdbinstance-controller", "ENABLE_APPDBINSTANCE_CONTROLLER", "run the AppDBInstance controller", boolSetter{&c.EnableAppDBInstance}},
+		{"tf-image", "TF_IMAGE", "terraform-operator pod image of the TerraformApplys", stringSetter(&c.TFDriver.Image)},
+		{"tf-iam-users-image", "TF_IAM_USERS_IMAGE", "Terraform 0.12 or newer pod image, required by AppDBs with spec.iamUsers", stringSetter(&c.TFDriver.IAMUsersImage)},
+		{"tf-image-pull-policy", "TF_IMAGE_PULL_POLICY", "image pull policy of the Terraform pods", setterFunc(func(value string) error {
+			c.TFDriver.ImagePullPolicy = corev1.PullPolicy(value)
+			return nil
+		})},
+		{"tf-backend-bucket", "TF_BACKEND_BUCKET", "GCS bucket of the Terraform state, derived from the project when empty", stringSetter(&c.TFDriver.BackendBucket)},
+		{"tf-backend-prefix", "TF_BACKEND_PREFIX", "prefix of the Terraform state in the backend bucket", stringSetter(&c.TFDriver.BackendPrefix)},
+		{"tf-max-attempts", "TF_MAX_ATTEMPTS", "maximum attempts of the Terraform pods", intSetter(&c.TFDriver.MaxAttempts)},
+		{"tf-google-provider-secret", "TF_GOOGLE_PROVIDER_SECRET", "Secret with the credentials of the Terraform google provider", stringSetter(&c.TFDriver.GoogleProviderConfigSecret)},
+		{"vault-addr", "VAULT_ADDR", "Vault address, the vaultDynamic credentials mode is disabled when empty", stringSetter(&c.Vault.Address)},
+		{"vault-token", "VAULT_TOKEN", "Vault token", stringSetter(&c.Vault.Token)},
+		{"vault-token-file", "VAULT_TOKEN_FILE", "file with the Vault token, used when no token is given", stringSetter(&c.Vault.TokenFile)},
+		{"vault-database-mount", "VAULT_DATABASE_MOUNT", "mount path of the Vault database secrets engine", stringSetter(&c.Vault.DatabaseMount)},
+		{"vault-cacert", "VAULT_CACERT", "CA certificate of the Vault server", stringSetter(&c.Vault.CACertFile)},
+		{"webhook-tls-cert-file", "WEBHOOK_TLS_CERT_FILE", "TLS certificate of the sync webhook, TLS is enabled when the certificate and key are set", stringSetter(&c.Webhook.TLSCertFile)},
+		{"webhook-tls-key-file", "WEBHOOK_TLS_KEY_FILE", "TLS key of the sync webhook", stringSetter(&c.Webhook.TLSKeyFile)},
+		{"webhook-auth-mode", "WEBHOOK_AUTH_MODE", "authentication of the sync requests: none, token, mtls or tokenReview", setterFunc(func(value string) error {
+			c.Webhook.AuthMode = webhookv1.AuthMode(value)
+			return nil
+		})},
+		{"webhook-auth-token", "WEBHOOK_AUTH_TOKEN", "shared token of the token auth mode", stringSetter(&c.Webhook.Token)},
+		{"webhook-auth-token-file", "WEBHOOK_AUTH_TOKEN_FILE", "file with the shared token of the token auth mode, used when no token is given", stringSetter(&c.Webhook.TokenFile)},
+		{"webhook-client-ca-file", "WEBHOOK_CLIENT_CA_FILE", "CA of the client certificates of the mtls auth mode", stringSetter(&c.Webhook.ClientCAFile)},
+		{"webhook-allowed-client-names", "WEBHOOK_ALLOWED_CLIENT_NAMES", "comma separated common names of the client certificates allowed by the mtls auth mode", listSetter(&c.Webhook.AllowedClientNames)},
+		{"webhook-allowed-users", "WEBHOOK_ALLOWED_USERS", "comma separated users allowed by the tokenReview auth mode", listSetter(&c.Webhook.AllowedUsers)},
+		{"controller-mode", "CONTROLLER_MODE", "webhook to serve the metacontroller sync hooks, manager to reconcile without metacontroller", setterFunc(func(value string) error {
+			c.Standalone.Mode = standalone.Mode(value)
+			return nil
+		})},
+		{"leader-elect", "LEADER_ELECT", "use leader election in manager mode", boolSetter{&c.Standalone.LeaderElection}},
+		{"leader-election-id", "LEADER_ELECTION_ID", "name of the leader election lock", stringSetter(&c.Standalone.LeaderElectionID)},
+		{"leader-election-namespace", "LEADER_ELECTION_NAMESPACE", "namespace of the leader election lock", stringSetter(&c.Standalone.LeaderElectionNamespace)},
+		{"max-concurrent-reconciles", "MAX_CONCURRENT_RECONCILES", "concurrent reconciles per controller in manager mode", intSetter(&c.Standalone.MaxConcurrentReconciles)},
+		{"resync-period", "RESYNC_PERIOD", "resync period of the parents in manager mode", durationSetter(&c.Standalone.ResyncPeriod)},
+		{"log-level", "LOG_LEVEL", "minimum log level: debug, info, warn or error", stringSetter(&c.Logging.Level)},
+		{"log-format", "LOG_FORMAT", "log format: json or text", stringSetter(&c.Logging.Format)},
+		{"http-debug", "HTTP_DEBUG", "log the sync requests and responses with the secrets redacted", boolSetter{&c.Logging.HTTPDebug}},
+	}
+}
+
+// AddFlags registers the command line flags of the config, the flags override the environment and the config file in LoadAndValidate.
+func (c *Config) AddFlags(fs *flag.FlagSet) {
+	fs.String("config", "", "path to a YAML config file with the flag names as keys, also read from CONFIG_FILE")
+	for _, o := range c.options() {
+		if fs.Lookup(o.name) != nil {
+			// Flags registered by a dependency, like -kubeconfig of controller-runtime, are read by name.
+			continue
+		}
+		if s, ok := o.set.(boolSetter); ok == true {
+			// The default is not applied, only flags that are set override the environment and the config file.
+			fs.Bool(o.name, *s.field, o.usage)
+			continue
+		}
+		fs.String(o.name, "", o.usage)
+	}
+	c.flags = fs
+}
+
+// LoadAndValidate reads the config from the config file, the environment and the flags, validates the settings of the drivers and webhooks and creates the Kubernetes clients.
+// The in-cluster config is used unless a kubeconfig is given, outside of a cluster the kubeconfig from KUBECONFIG or ~/.kube/config is used.
+func (c *Config) LoadAndValidate() error {
+	var err error
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	// Configured first so that the rest of the config is logged in the configured format.
+	if err := c.Logging.LoadAndValidate(); err != nil {
+		return fmt.Errorf("Invalid logging config: %v", err)
+	}
+	if c.ConfigFile != "" {
+		log.Printf("[INFO] Loaded config file %s", c.ConfigFile)
+	}
+
+	if c.EnableAppDB == false && c.EnableAppDBInstance == false {
+		return fmt.Errorf("At least one of ENABLE_APPDB_CONTROLLER or ENABLE_APPDBINSTANCE_CONTROLLER must be true")
+	}
+
+	if err := c.Webhook.LoadAndValidate(); err != nil {
+		return fmt.Errorf("Invalid webhook config: %v", err)
+	}
+
+	if err := c.Standalone.LoadAndValidate(); err != nil {
+		return fmt.Errorf("Invalid controller mode config: %v", err)
+	}
+
+	// Vault is optional, only required for AppDBs with spec.credentials.mode: vaultDynamic
+	if c.Vault.Enabled() == true {
+		if err := c.Vault.LoadAndValidate(); err != nil {
+			return fmt.Errorf("Invalid vault config: %v", err)
+		}
+	} else if c.EnableAppDB == true {
+		log.Printf("[INFO] No VAULT_ADDR given, vaultDynamic credentials mode is disabled")
+	}
+
+	if err := c.loadProject(); err != nil {
+		return err
+	}
+
+	if err := c.TFDriver.LoadAndValidate(c.Project); err != nil {
+		return fmt.Errorf("Invalid terraform driver config: %v", err)
+	}
+
+	clusterConfig, err := c.loadRestConfig()
+	if err != nil {
+		return err
+	}
+	c.RestConfig = clusterConfig
+
+	clientset, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return err
+	}
+	c.Clientset = clientset
+
+	// The AppDB controller reads AppDBInstances and AppDBInstanceClasses, the AppDBInstance controller reads the AppDBs placed on the instance.
+	resources := []schema.GroupVersionResource{kubev1.AppDBResource, kubev1.AppDBInstanceResource}
+	if c.EnableAppDB == true {
+		resources = append(resources, kubev1.AppDBInstanceClassResource)
+	}
+	kubeClient, err := kubev1.NewClient(clusterConfig, resources...)
+	if err != nil {
+		return err
+	}
+	c.KubeClient = kubeClient
+
+	return nil
+}
+
+// load sets the options from the config file, the environment and the flags, in increasing order of precedence.
+func (c *Config) load() error {
+	flagValues := make(map[string]string, 0)
+	if c.flags != nil {
+		c.flags.Visit(func(f *flag.Flag) {
+			flagValues[f.Name] = f.Value.String()
+		})
+	}
+
+	// CONFIG_FILE is optional
+	if configFile, ok := os.LookupEnv("CONFIG_FILE"); ok == true {
+		c.ConfigFile = configFile
+	}
+	if configFile, ok := flagValues["config"]; ok == true {
+		c.ConfigFile = configFile
+	}
+	if c.ConfigFile != "" {
+		if err := c.loadFile(c.ConfigFile); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range c.options() {
+		if o.env != "" {
+			if value, ok := os.LookupEnv(o.env); ok == true {
+				if err := o.set.Set(value); err != nil {
+					return fmt.Errorf("Invalid value for %s: %v", o.env, err)
+				}
+			}
+		}
+		if value, ok := flagValues[o.name]; ok == true {
+			if err := o.set.Set(value); err != nil {
+				return fmt.Errorf("Invalid value for -%s: %v", o.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadFile applies the settings from the YAML config file.
+func (c *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read config file %s: %v", path, err)
+	}
+
+	values := make(map[string]interface{}, 0)
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("Failed to parse config file %s: %v", path, err)
+	}
+
+	options := make(map[string]option, 0)
+	for _, o := range c.options() {
+		options[o.name] = o
+	}
+
+	for k, v := range values {
+		o, ok := options[k]
+		if ok == false {
+			return fmt.Errorf("Unknown setting in config file %s: %s", path, k)
+		}
+		if err := o.set.Set(fileValue(v)); err != nil {
+			return fmt.Errorf("Invalid value for %s in config file %s: %v", k, path, err)
+		}
+	}
+
+	return nil
+}
+
+// fileValue returns the flag value of a setting in the config file, lists are comma separated.
+func fileValue(v interface{}) string {
+	if list, ok := v.([]interface{}); ok == true {
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, fmt.Sprintf("%v", item))
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// loadProject reads the missing project ID and number from the metadata server.
+// The project is optional, drivers that require a GCP project are disabled without it.
+func (c *Config) loadProject() error {
+	var err error
+
+	if c.Project != "" && c.ProjectNum != "" {
+		return nil
+	}
+
+	if c.UseMetadataServer == false || metadata.OnGCE() == false {
+		if c.Project == "" {
+			log.Printf("[INFO] No project given and the metadata server is not used, drivers that require a GCP project are disabled")
+		}
+		return nil
+	}
+
+	if c.Project == "" {
+		log.Printf("[INFO] Fetching Project ID from Compute metadata API...")
+		c.Project, err = metadata.ProjectID()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.ProjectNum == "" {
+		log.Printf("[INFO] Fetching Numeric Project ID from Compute metadata API...")
+		c.ProjectNum, err = metadata.NumericProjectID()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadRestConfig returns the config of the Kubernetes clients.
+func (c *Config) loadRestConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+
+	if c.Kubeconfig == "" {
+		clusterConfig, err := rest.InClusterConfig()
+		if err == nil {
+			return clusterConfig, nil
+		}
+		if err != rest.ErrNotInCluster {
+			return nil, err
+		}
+		log.Printf("[INFO] Not running in a cluster, using kubeconfig")
+	} else {
+		loadingRules.ExplicitPath = c.Kubeconfig
+	}
+
+	clusterConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load kubeconfig: %v", err)
+	}
+
+	return clusterConfig, nil
+}
+
+func stringSetter(field *string) setter {
+	return setterFunc(func(value string) error {
+		*field = value
+		return nil
+	})
+}
+
+func intSetter(field *int) setter {
+	return setterFunc(func(value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s, must be an integer", value)
+		}
+		*field = i
+		return nil
+	})
+}
+
+func durationSetter(field *time.Duration) setter {
+	return setterFunc(func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s, must be a duration like 10s", value)
+		}
+		*field = d
+		return nil
+	})
+}
+
+// listSetter sets a comma separated list, empty items are skipped.
+func listSetter(field *[]string) setter {
+	return setterFunc(func(value string) error {
+		list := make([]string, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*field = list
+		return nil
+	})
+}
@@ -0,0 +1,135 @@
+package operator
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/standalone"
+	webhookv1 "github.com/danisla/appdb-operator/pkg/webhook"
+)
+
+func TestAddFlags(t *testing.T) {
+	c := &Config{EnableAppDB: true, EnableAppDBInstance: true}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.AddFlags(fs)
+
+	// Bool flags can be given without a value.
+	if err := fs.Parse([]string{"-metadata-server", "-enable-appdb-controller=false", "-project", "my-project"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for name, want := range map[string]string{"metadata-server": "true", "enable-appdb-controller": "false", "project": "my-project"} {
+		if got := fs.Lookup(name).Value.String(); got != want {
+			t.Errorf("Flag -%s: expected %s, got: %s", name, want, got)
+		}
+	}
+
+	for _, o := range c.options() {
+		if o.name == "enable-appdbinstance-controller" && fs.Lookup(o.name).DefValue != "true" {
+			t.Errorf("Expected default of -%s to be the config value, got: %s", o.name, fs.Lookup(o.name).DefValue)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+tf-backend-bucket: file-bucket
+tf-max-attempts: 2
+vault-addr: https://vault.example.com
+webhook-auth-mode: tokenReview
+webhook-allowed-users:
+- system:serviceaccount:metacontroller:metacontroller
+- system:serviceaccount:other:metacontroller
+controller-mode: manager
+resync-period: 30s
+log-level: debug
+`
+	if err := ioutil.WriteFile(configFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Setenv("CONFIG_FILE", configFile)
+	t.Setenv("TF_MAX_ATTEMPTS", "3")
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("MAX_CONCURRENT_RECONCILES", "5")
+	t.Setenv("LEADER_ELECT", "false")
+	t.Setenv("LOG_FORMAT", "text")
+	t.Setenv("HTTP_DEBUG", "1")
+
+	c := &Config{Standalone: standalone.Config{LeaderElection: true}}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.AddFlags(fs)
+	if err := fs.Parse([]string{"-tf-max-attempts", "6", "-webhook-tls-cert-file", "/tls/tls.crt", "-log-format", "json"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := c.load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"file", c.TFDriver.BackendBucket, "file-bucket"},
+		{"flag over env over file", c.TFDriver.MaxAttempts, 6},
+		{"file vault address", c.Vault.Address, "https://vault.example.com"},
+		{"env vault token", c.Vault.Token, "root"},
+		{"file auth mode", c.Webhook.AuthMode, webhookv1.AuthModeTokenReview},
+		{"file list", c.Webhook.AllowedUsers, []string{"system:serviceaccount:metacontroller:metacontroller", "system:serviceaccount:other:metacontroller"}},
+		{"flag webhook cert", c.Webhook.TLSCertFile, "/tls/tls.crt"},
+		{"file controller mode", c.Standalone.Mode, standalone.ModeManager},
+		{"file duration", c.Standalone.ResyncPeriod, 30 * time.Second},
+		{"env int", c.Standalone.MaxConcurrentReconciles, 5},
+		{"env bool", c.Standalone.LeaderElection, false},
+		{"file log level", c.Logging.Level, "debug"},
+		{"flag over env log format", c.Logging.Format, "json"},
+		{"env http debug", c.Logging.HTTPDebug, true},
+	}
+	for _, tc := range tests {
+		if reflect.DeepEqual(tc.got, tc.want) == false {
+			t.Errorf("%s: expected %v, got: %v", tc.name, tc.want, tc.got)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{"TF_MAX_ATTEMPTS", "four"},
+		{"MAX_CONCURRENT_RECONCILES", "1.5"},
+		{"RESYNC_PERIOD", "10"},
+		{"LEADER_ELECT", "maybe"},
+		{"HTTP_DEBUG", "yes"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.env, func(t *testing.T) {
+			t.Setenv(tc.env, tc.value)
+			c := &Config{}
+			if err := c.load(); err == nil {
+				t.Errorf("Expected error for %s=%s", tc.env, tc.value)
+			}
+		})
+	}
+}
+
+func TestLoadUnknownFileSetting(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(configFile, []byte("tf-max-attempt: 2\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Setenv("CONFIG_FILE", configFile)
+
+	c := &Config{}
+	if err := c.load(); err == nil {
+		t.Errorf("Expected error for unknown setting")
+	}
+}
@@ -0,0 +1,21 @@
+package operator
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// CalcParentSig returns a signature of the spec, used to detect changes to the parent spec that require a child to be updated.
+func CalcParentSig(spec interface{}, addStr string) string {
+	hasher := sha1.New()
+	data, err := json.Marshal(&spec)
+	if err != nil {
+		log.Printf("[ERROR] Failed to convert parent spec to JSON, this is a bug.\n")
+		return ""
+	}
+	hasher.Write([]byte(data))
+	hasher.Write([]byte(addStr))
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
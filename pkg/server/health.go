@@ -0,0 +1,49 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// Check is a named readiness check, Func returns an error when the operator cannot sync.
+type Check struct {
+	Name string
+	Func func() error
+}
+
+// HealthzHandler returns OK while the process is running, for the liveness probe.
+func HealthzHandler() func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "OK\n")
+	}
+}
+
+// ReadyzHandler returns OK when all checks pass, for the readiness probe.
+// The failed checks are listed in the response, the handler also fails once a shutdown has started.
+func ReadyzHandler(checks ...Check) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&shuttingDown) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "shutting down\n")
+			return
+		}
+
+		failed := make([]string, 0)
+		for _, c := range checks {
+			if err := c.Func(); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", c.Name, err))
+			}
+		}
+
+		if len(failed) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			for _, f := range failed {
+				fmt.Fprintf(w, "%s\n", f)
+			}
+			return
+		}
+
+		fmt.Fprint(w, "OK\n")
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HealthzHandler()(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "OK\n" {
+		t.Errorf("Expected 200 OK, got: %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	ok := Check{Name: "kubernetes", Func: func() error { return nil }}
+	failing := Check{Name: "terraform", Func: func() error { return fmt.Errorf("Missing Terraform backend bucket") }}
+
+	tests := []struct {
+		name         string
+		checks       []Check
+		shuttingDown bool
+		wantStatus   int
+		wantBody     string
+	}{
+		{"no checks", nil, false, http.StatusOK, "OK\n"},
+		{"checks pass", []Check{ok}, false, http.StatusOK, "OK\n"},
+		{"check fails", []Check{ok, failing}, false, http.StatusServiceUnavailable, "terraform: Missing Terraform backend bucket\n"},
+		{"shutting down", []Check{ok}, true, http.StatusServiceUnavailable, "shutting down\n"},
+	}
+
+	for _, tc := range tests {
+		if tc.shuttingDown == true {
+			atomic.StoreInt32(&shuttingDown, 1)
+		}
+
+		rec := httptest.NewRecorder()
+		ReadyzHandler(tc.checks...)(rec, httptest.NewRequest("GET", "/readyz", nil))
+		atomic.StoreInt32(&shuttingDown, 0)
+
+		if rec.Code != tc.wantStatus {
+			t.Errorf("%s: expected status %d, got: %d", tc.name, tc.wantStatus, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), tc.wantBody) == false {
+			t.Errorf("%s: expected body %q, got: %q", tc.name, tc.wantBody, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	DEFAULT_READ_HEADER_TIMEOUT = 10 * time.Second
+	DEFAULT_READ_TIMEOUT        = 30 * time.Second
+	DEFAULT_WRITE_TIMEOUT       = 60 * time.Second
+	DEFAULT_IDLE_TIMEOUT        = 120 * time.Second
+
+	// DEFAULT_SHUTDOWN_TIMEOUT is shorter than the default pod terminationGracePeriodSeconds of 30 seconds.
+	DEFAULT_SHUTDOWN_TIMEOUT = 25 * time.Second
+)
+
+// shuttingDown is set when a shutdown signal is received, /readyz fails from then on.
+var shuttingDown int32
+
+// Server is an http server with optional TLS.
+type Server struct {
+	*http.Server
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// New returns a server for the handler with the default timeouts.
+// The write timeout is longer than the sync webhook timeout of metacontroller so that slow syncs are not cut off.
+func New(addr string, handler http.Handler) Server {
+	return Server{
+		Server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: DEFAULT_READ_HEADER_TIMEOUT,
+			ReadTimeout:       DEFAULT_READ_TIMEOUT,
+			WriteTimeout:      DEFAULT_WRITE_TIMEOUT,
+			IdleTimeout:       DEFAULT_IDLE_TIMEOUT,
+		},
+	}
+}
+
+// NewTLS returns a server for the handler with the default timeouts that serves TLS with the certificate and key files.
+func NewTLS(addr string, handler http.Handler, certFile, keyFile string) Server {
+	s := New(addr, handler)
+	s.TLSCertFile = certFile
+	s.TLSKeyFile = keyFile
+	return s
+}
+
+func (s Server) listenAndServe() error {
+	if s.TLSCertFile != "" && s.TLSKeyFile != "" {
+		return s.ListenAndServeTLS(s.TLSCertFile, s.TLSKeyFile)
+	}
+	return s.ListenAndServe()
+}
+
+// Run serves until SIGINT or SIGTERM is received or a server fails, then stops accepting connections and
+// waits up to shutdownTimeout for the in-flight requests, like syncs, to finish.
+func Run(shutdownTimeout time.Duration, servers ...Server) error {
+	errCh := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s Server) {
+			if err := s.listenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("Server on %s failed: %v", s.Addr, err)
+			}
+		}(s)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	var runErr error
+	select {
+	case sig := <-sigCh:
+		log.Printf("[INFO] Received %s, shutting down", sig)
+	case runErr = <-errCh:
+		log.Printf("[ERROR] %v, shutting down", runErr)
+	}
+
+	atomic.StoreInt32(&shuttingDown, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	for _, s := range servers {
+		if err := s.Shutdown(ctx); err != nil {
+			log.Printf("[WARN] Failed to drain in-flight requests on %s: %v", s.Addr, err)
+		}
+	}
+
+	return runErr
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a local address that is not in use.
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// waitForServer waits until the server on addr accepts connections.
+func waitForServer(t *testing.T, addr string) {
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Server on %s did not start", addr)
+}
+
+// runTestServers starts Run in the background and returns the channel of its result.
+// SIGTERM is also delivered to the test so that the process is not stopped before Run registers its handler.
+func runTestServers(t *testing.T, servers ...Server) chan error {
+	sigCh := make(chan os.Signal, 10)
+	signal.Notify(sigCh, syscall.SIGTERM)
+	t.Cleanup(func() {
+		signal.Stop(sigCh)
+		atomic.StoreInt32(&shuttingDown, 0)
+	})
+
+	runCh := make(chan error, 1)
+	go func() {
+		runCh <- Run(5*time.Second, servers...)
+	}()
+	return runCh
+}
+
+// stop sends SIGTERM until Run returns.
+func stop(t *testing.T, runCh chan error) error {
+	for i := 0; i < 50; i++ {
+		syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+		select {
+		case err := <-runCh:
+			return err
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	t.Fatalf("Run did not return after SIGTERM")
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	s := New(":8080", http.NewServeMux())
+	if s.ReadHeaderTimeout != DEFAULT_READ_HEADER_TIMEOUT || s.ReadTimeout != DEFAULT_READ_TIMEOUT || s.WriteTimeout != DEFAULT_WRITE_TIMEOUT || s.IdleTimeout != DEFAULT_IDLE_TIMEOUT {
+		t.Errorf("Expected the default timeouts, got: %+v", s.Server)
+	}
+
+	s = NewTLS(":8443", http.NewServeMux(), "tls.crt", "tls.key")
+	if s.TLSCertFile != "tls.crt" || s.TLSKeyFile != "tls.key" {
+		t.Errorf("Expected TLS files to be set, got: %s %s", s.TLSCertFile, s.TLSKeyFile)
+	}
+}
+
+func TestRunRouting(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", HealthzHandler())
+	mux.HandleFunc("/readyz", ReadyzHandler())
+	mux.HandleFunc("/sync/appdb", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("synced"))
+	})
+
+	addr := freeAddr(t)
+	runCh := runTestServers(t, New(addr, mux))
+	waitForServer(t, addr)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/healthz", http.StatusOK, "OK\n"},
+		{"/readyz", http.StatusOK, "OK\n"},
+		{"/sync/appdb", http.StatusOK, "synced"},
+		{"/sync/other", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tc := range tests {
+		resp, err := http.Get("http://" + addr + tc.path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.path, err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tc.wantStatus || string(body) != tc.wantBody {
+			t.Errorf("%s: expected %d %q, got: %d %q", tc.path, tc.wantStatus, tc.wantBody, resp.StatusCode, string(body))
+		}
+	}
+
+	if err := stop(t, runCh); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestRunShutdownDrainsRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sync/appdb", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("synced"))
+	})
+	readyz := ReadyzHandler()
+
+	addr := freeAddr(t)
+	runCh := runTestServers(t, New(addr, mux))
+	waitForServer(t, addr)
+
+	respCh := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/sync/appdb")
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		respCh <- resp
+	}()
+	<-started
+
+	stopCh := make(chan error, 1)
+	go func() {
+		stopCh <- stop(t, runCh)
+	}()
+
+	// Readiness fails once the shutdown started, while the in-flight sync is still running.
+	for i := 0; atomic.LoadInt32(&shuttingDown) == 0; i++ {
+		if i == 100 {
+			t.Fatalf("Shutdown did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	rec := httptest.NewRecorder()
+	readyz(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz to fail during shutdown, got: %d", rec.Code)
+	}
+
+	select {
+	case <-stopCh:
+		t.Fatalf("Run returned before the in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-stopCh; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	resp := <-respCh
+	if resp == nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the in-flight request to complete, got: %v", resp)
+	}
+	resp.Body.Close()
+}
+
+func TestRunServerError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer l.Close()
+
+	// The address is in use, Run returns the error of the server.
+	runCh := runTestServers(t, New(l.Addr().String(), http.NewServeMux()))
+	select {
+	case err := <-runCh:
+		if err == nil {
+			t.Errorf("Expected error for an address in use")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after the server failed")
+	}
+}
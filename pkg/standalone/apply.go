@@ -0,0 +1,263 @@
+package standalone
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/danisla/appdb-operator/pkg/logging"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// observedChildren returns the children of the parent by key and name, children are the objects in the namespace of the parent with the parent as controller owner.
+func (r *reconciler) observedChildren(parent *unstructured.Unstructured) (map[string]map[string]*unstructured.Unstructured, error) {
+	observed := make(map[string]map[string]*unstructured.Unstructured, 0)
+
+	for _, child := range r.ChildResources {
+		observed[child.Key()] = make(map[string]*unstructured.Unstructured, 0)
+
+		objs, err := r.kubeClient.List(child.GroupVersionResource(), parent.GetNamespace())
+		if err != nil {
+			return observed, err
+		}
+
+		for _, obj := range objs {
+			if ref := metav1.GetControllerOf(obj); ref != nil && ref.UID == parent.GetUID() {
+				observed[child.Key()][obj.GetName()] = obj.DeepCopy()
+			}
+		}
+	}
+
+	return observed, nil
+}
+
+func (r *reconciler) childResource(apiVersion, kind string) (ChildResource, bool) {
+	for _, child := range r.ChildResources {
+		if child.APIVersion == apiVersion && child.Kind == kind {
+			return child, true
+		}
+	}
+	return ChildResource{}, false
+}
+
+// applyChildren creates the desired children that do not exist, updates the existing ones according to the update strategy and deletes the observed children that are no longer desired.
+func (r *reconciler) applyChildren(ctx context.Context, parent *unstructured.Unstructured, observed map[string]map[string]*unstructured.Unstructured, desired []*unstructured.Unstructured) error {
+	logger := logging.FromContext(ctx)
+	errs := make([]error, 0)
+
+	claimed := make(map[string]map[string]bool, 0)
+
+	for _, child := range desired {
+		resource, ok := r.childResource(child.GetAPIVersion(), child.GetKind())
+		if ok == false {
+			errs = append(errs, fmt.Errorf("%s/%s: Unsupported child resource %s", child.GetKind(), child.GetName(), child.GetAPIVersion()))
+			continue
+		}
+
+		if child.GetNamespace() == "" {
+			child.SetNamespace(parent.GetNamespace())
+		}
+		if child.GetNamespace() != parent.GetNamespace() {
+			errs = append(errs, fmt.Errorf("%s/%s: Child must be in the namespace of the parent: %s", child.GetKind(), child.GetName(), parent.GetNamespace()))
+			continue
+		}
+		setControllerRef(child, parent)
+
+		if claimed[resource.Key()] == nil {
+			claimed[resource.Key()] = make(map[string]bool, 0)
+		}
+		claimed[resource.Key()][child.GetName()] = true
+
+		client := r.kubeClient.Resource(resource.GroupVersionResource()).Namespace(child.GetNamespace())
+		childLogger := logger.WithChild(child.GetKind(), child.GetName())
+
+		current, ok := observed[resource.Key()][child.GetName()]
+		if ok == false {
+			if _, err := client.Create(child, metav1.CreateOptions{}); err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: Failed to create: %v", child.GetKind(), child.GetName(), err))
+				continue
+			}
+			childLogger.Infof("Created child")
+			continue
+		}
+
+		if resource.UpdateStrategy != UpdateStrategyInPlace || current.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		updated := mergeChild(current, child)
+		if equality.Semantic.DeepEqual(current.Object, updated.Object) {
+			continue
+		}
+		if _, err := client.Update(updated, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: Failed to update: %v", child.GetKind(), child.GetName(), err))
+			continue
+		}
+		childLogger.Infof("Updated child")
+	}
+
+	for key, children := range observed {
+		resource, _ := r.childResourceByKey(key)
+		for name, child := range children {
+			if claimed[key][name] == true || child.GetDeletionTimestamp() != nil {
+				continue
+			}
+			uid := child.GetUID()
+			propagation := metav1.DeletePropagationBackground
+			err := r.kubeClient.Resource(resource.GroupVersionResource()).Namespace(child.GetNamespace()).Delete(name, &metav1.DeleteOptions{
+				Preconditions:     &metav1.Preconditions{UID: &uid},
+				PropagationPolicy: &propagation,
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s/%s: Failed to delete: %v", child.GetKind(), name, err))
+				continue
+			}
+			logger.WithChild(child.GetKind(), name).Infof("Deleted child")
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *reconciler) childResourceByKey(key string) (ChildResource, bool) {
+	for _, child := range r.ChildResources {
+		if child.Key() == key {
+			return child, true
+		}
+	}
+	return ChildResource{}, false
+}
+
+// updateStatus replaces the status of the parent and sets status.observedGeneration, the parent is only updated when the status changed.
+func (r *reconciler) updateStatus(parent *unstructured.Unstructured, status map[string]interface{}) error {
+	status["observedGeneration"] = parent.GetGeneration()
+
+	if equality.Semantic.DeepEqual(parent.Object["status"], status) {
+		return nil
+	}
+
+	updated := parent.DeepCopy()
+	updated.Object["status"] = status
+	_, err := r.kubeClient.Resource(r.ParentResource).Namespace(parent.GetNamespace()).UpdateStatus(updated, metav1.UpdateOptions{})
+	return err
+}
+
+// setControllerRef sets the parent as the controller owner of the child.
+func setControllerRef(child, parent *unstructured.Unstructured) {
+	isController := true
+	blockOwnerDeletion := true
+
+	refs := make([]metav1.OwnerReference, 0)
+	for _, ref := range child.GetOwnerReferences() {
+		if ref.UID != parent.GetUID() {
+			refs = append(refs, ref)
+		}
+	}
+	refs = append(refs, metav1.OwnerReference{
+		APIVersion:         parent.GetAPIVersion(),
+		Kind:               parent.GetKind(),
+		Name:               parent.GetName(),
+		UID:                parent.GetUID(),
+		Controller:         &isController,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	})
+	child.SetOwnerReferences(refs)
+}
+
+// mergeChild returns a copy of current with the fields of desired applied.
+// Labels and annotations are merged, status is kept and fields that are only set in current, like the defaults set by the API server, are kept so that an unchanged child compares equal.
+func mergeChild(current, desired *unstructured.Unstructured) *unstructured.Unstructured {
+	merged := current.DeepCopy()
+
+	desiredObj := desired.DeepCopy().Object
+	if desired.GetKind() == "Secret" {
+		// The API server stores stringData as data.
+		moveStringData(desiredObj)
+	}
+
+	for k, v := range desiredObj {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		merged.Object[k] = mergeValue(merged.Object[k], v)
+	}
+
+	if len(desired.GetLabels()) > 0 {
+		labels := merged.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, 0)
+		}
+		for k, v := range desired.GetLabels() {
+			labels[k] = v
+		}
+		merged.SetLabels(labels)
+	}
+
+	if len(desired.GetAnnotations()) > 0 {
+		annotations := merged.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 0)
+		}
+		for k, v := range desired.GetAnnotations() {
+			annotations[k] = v
+		}
+		merged.SetAnnotations(annotations)
+	}
+
+	merged.SetOwnerReferences(desired.GetOwnerReferences())
+
+	return merged
+}
+
+func mergeValue(current, desired interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if ok == false {
+			return d
+		}
+		out := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			out[k] = v
+		}
+		for k, v := range d {
+			out[k] = mergeValue(c[k], v)
+		}
+		return out
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if ok == false || len(c) != len(d) {
+			return d
+		}
+		out := make([]interface{}, len(d))
+		for i := range d {
+			out[i] = mergeValue(c[i], d[i])
+		}
+		return out
+	default:
+		return runtime.DeepCopyJSONValue(desired)
+	}
+}
+
+func moveStringData(obj map[string]interface{}) {
+	stringData, ok := obj["stringData"].(map[string]interface{})
+	if ok == false {
+		return
+	}
+	data, ok := obj["data"].(map[string]interface{})
+	if ok == false {
+		data = make(map[string]interface{}, len(stringData))
+	}
+	for k, v := range stringData {
+		if s, ok := v.(string); ok == true {
+			data[k] = base64.StdEncoding.EncodeToString([]byte(s))
+		}
+	}
+	obj["data"] = data
+	delete(obj, "stringData")
+}
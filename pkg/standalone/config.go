@@ -0,0 +1,64 @@
+package standalone
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Mode is how the sync of the parents is triggered.
+type Mode string
+
+const (
+	// ModeWebhook serves the sync hook of a metacontroller CompositeController, the default.
+	ModeWebhook Mode = "webhook"
+	// ModeManager runs the sync in a controller-runtime manager with its own watches, metacontroller is not required.
+	ModeManager Mode = "manager"
+
+	DEFAULT_LEADER_ELECTION_NAMESPACE = "metacontroller"
+	DEFAULT_MAX_CONCURRENT_RECONCILES = 2
+	DEFAULT_RESYNC_PERIOD             = 10 * time.Second
+)
+
+// Config is the config of the standalone controller mode, the settings are read by the operator config.
+type Config struct {
+	Mode                    Mode
+	LeaderElection          bool
+	LeaderElectionID        string
+	LeaderElectionNamespace string
+	MaxConcurrentReconciles int
+	ResyncPeriod            time.Duration
+}
+
+// LoadAndValidate validates the settings read by the operator config.
+func (c *Config) LoadAndValidate() error {
+
+	switch c.Mode {
+	case ModeWebhook:
+		return nil
+	case ModeManager:
+	default:
+		return fmt.Errorf("Unsupported CONTROLLER_MODE: %s, must be one of: %s, %s", c.Mode, ModeWebhook, ModeManager)
+	}
+
+	if c.LeaderElectionID == "" {
+		return fmt.Errorf("Missing LEADER_ELECTION_ID")
+	}
+
+	if c.LeaderElectionNamespace == "" {
+		return fmt.Errorf("Missing LEADER_ELECTION_NAMESPACE")
+	}
+
+	if c.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("Invalid number for MAX_CONCURRENT_RECONCILES: %d, must be positive integer", c.MaxConcurrentReconciles)
+	}
+
+	// RESYNC_PERIOD matches the resyncPeriodSeconds of the CompositeControllers by default.
+	if c.ResyncPeriod <= 0 {
+		return fmt.Errorf("Invalid duration for RESYNC_PERIOD: %s, must be positive duration like 10s", c.ResyncPeriod)
+	}
+
+	log.Printf("[INFO] Running in %s mode, leader election: %t", c.Mode, c.LeaderElection)
+
+	return nil
+}
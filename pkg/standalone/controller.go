@@ -0,0 +1,188 @@
+// Package standalone runs the sync of an operator in a controller-runtime manager, for clusters without metacontroller.
+// The manager watches the parents and their children and applies the sync response like a metacontroller CompositeController would.
+package standalone
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danisla/appdb-operator/pkg/hook"
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	"github.com/danisla/appdb-operator/pkg/logging"
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Controller is the definition of a standalone controller, the equivalent of a CompositeController.
+type Controller struct {
+	Name           string
+	ParentResource schema.GroupVersionResource
+	// ParentType is the typed parent, used to map the owner references of the children to the parent.
+	ParentType     runtime.Object
+	ChildResources []ChildResource
+	Watches        []Watch
+	Sync           hook.SyncFunc
+	// Finalize is optional, the equivalent of the finalize hook of a CompositeController.
+	// When set, a finalizer is added to the parents and removed after Finalize returns a finalized response.
+	Finalize hook.SyncFunc
+}
+
+// NewManager creates a controller-runtime manager with the config, the metrics of the manager are disabled in favor of the operator metrics.
+func NewManager(restConfig *rest.Config, config *Config) (manager.Manager, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := appdbv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return manager.New(restConfig, manager.Options{
+		Scheme:                  scheme,
+		LeaderElection:          config.LeaderElection,
+		LeaderElectionID:        config.LeaderElectionID,
+		LeaderElectionNamespace: config.LeaderElectionNamespace,
+		MetricsBindAddress:      "0",
+	})
+}
+
+// Add adds the controller to the manager.
+// The parents and children are read from the informers of the kube client, which must be started after Add.
+func Add(mgr manager.Manager, kubeClient *kubev1.Client, config *Config, c Controller) error {
+	r := &reconciler{
+		Controller: c,
+		kubeClient: kubeClient,
+		config:     config,
+	}
+
+	ctrl, err := controller.New(c.Name, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ctrl.Watch(&source.Informer{Informer: kubeClient.Informer(c.ParentResource)}, &handler.EnqueueRequestForObject{}); err != nil {
+		return fmt.Errorf("Failed to watch %s: %v", c.ParentResource.String(), err)
+	}
+
+	for _, child := range c.ChildResources {
+		if err := ctrl.Watch(&source.Informer{Informer: kubeClient.Informer(child.GroupVersionResource())}, &handler.EnqueueRequestForOwner{OwnerType: c.ParentType, IsController: true}); err != nil {
+			return fmt.Errorf("Failed to watch %s: %v", child.GroupVersionResource().String(), err)
+		}
+	}
+
+	for _, w := range c.Watches {
+		mapFunc := w.Map
+		toRequests := handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			requests := make([]reconcile.Request, 0)
+			obj, ok := o.Object.(*unstructured.Unstructured)
+			if ok == false {
+				return requests
+			}
+			for _, nn := range mapFunc(obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: nn})
+			}
+			return requests
+		})
+		if err := ctrl.Watch(&source.Informer{Informer: kubeClient.Informer(w.Resource)}, &handler.EnqueueRequestsFromMapFunc{ToRequests: toRequests}); err != nil {
+			return fmt.Errorf("Failed to watch %s: %v", w.Resource.String(), err)
+		}
+	}
+
+	return nil
+}
+
+type reconciler struct {
+	Controller
+	kubeClient *kubev1.Client
+	config     *Config
+}
+
+// Reconcile syncs the parent, errors are requeued by the rate limited work queue of the controller.
+// Successful syncs are requeued after the resync period, like the resyncPeriodSeconds of a CompositeController.
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	cached, err := r.kubeClient.Get(r.ParentResource, request.Namespace, request.Name)
+	if apierrors.IsNotFound(err) {
+		// The children are garbage collected through their owner references.
+		return reconcile.Result{}, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	parent := cached.DeepCopy()
+
+	if parent.GetDeletionTimestamp() != nil && (r.Finalize == nil || hasFinalizer(parent, r.finalizerName()) == false) {
+		return reconcile.Result{}, nil
+	}
+
+	requestID := logging.NewRequestID()
+	logger := logging.New().With(logging.KeyRequestID, requestID).ForObject(parent.GetKind(), parent.GetNamespace(), parent.GetName())
+	ctx := logging.NewContext(context.Background(), logger)
+
+	if r.Finalize != nil && parent.GetDeletionTimestamp() == nil && hasFinalizer(parent, r.finalizerName()) == false {
+		// The parent is synced again when the update is observed.
+		if err := r.addFinalizer(parent); err != nil {
+			logger.Errorf("Failed to add finalizer: %v", err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	observed, err := r.observedChildren(parent)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("Failed to list children of %s/%s: %v", parent.GetNamespace(), parent.GetName(), err)
+	}
+
+	if parent.GetDeletionTimestamp() != nil {
+		return r.finalize(ctx, parent, observed, requestID)
+	}
+
+	resp, err := r.Sync(ctx, &hook.SyncRequest{Parent: parent, Children: observed, RequestID: requestID})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.applyChildren(ctx, parent, observed, resp.Children); err != nil {
+		logger.Errorf("Failed to apply children: %v", err)
+		return reconcile.Result{}, err
+	}
+
+	if err := r.updateStatus(parent, resp.Status); err != nil {
+		logger.Errorf("Failed to update status: %v", err)
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: r.config.ResyncPeriod}, nil
+}
+
+// finalize runs the finalize hook of the deleted parent and removes the finalizer when the parent is finalized.
+// The status and children of the response are not applied, the children are garbage collected after the parent is deleted.
+func (r *reconciler) finalize(ctx context.Context, parent *unstructured.Unstructured, observed map[string]map[string]*unstructured.Unstructured, requestID string) (reconcile.Result, error) {
+	resp, err := r.Finalize(ctx, &hook.SyncRequest{Parent: parent, Children: observed, Finalizing: true, RequestID: requestID})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if resp.Finalized == false {
+		return reconcile.Result{RequeueAfter: r.config.ResyncPeriod}, nil
+	}
+
+	if err := r.removeFinalizer(parent); err != nil {
+		logging.FromContext(ctx).Errorf("Failed to remove finalizer: %v", err)
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
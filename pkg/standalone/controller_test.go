@@ -0,0 +1,241 @@
+package standalone
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/danisla/appdb-operator/pkg/hook"
+	kubev1 "github.com/danisla/appdb-operator/pkg/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	testConfigMaps = ChildResource{APIVersion: "v1", Kind: "ConfigMap", Resource: "configmaps", UpdateStrategy: UpdateStrategyInPlace}
+	testSecrets    = ChildResource{APIVersion: "v1", Kind: "Secret", Resource: "secrets"}
+)
+
+// testSync desires a ConfigMap with the value of the parent spec, and a Secret once the ConfigMap is observed, like the conditions of the controllers.
+func testSync(ctx context.Context, request *hook.SyncRequest) (*hook.SyncResponse, error) {
+	name := request.Parent.GetName()
+	value, _, _ := unstructured.NestedString(request.Parent.Object, "spec", "value")
+
+	config := &unstructured.Unstructured{}
+	config.SetAPIVersion("v1")
+	config.SetKind("ConfigMap")
+	config.SetName(name + "-config")
+	config.SetLabels(map[string]string{"app": name})
+	config.Object["data"] = map[string]interface{}{"value": value}
+	children := []*unstructured.Unstructured{config}
+
+	if _, ok := request.Children[testConfigMaps.Key()][config.GetName()]; ok == true {
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetName(name + "-credentials")
+		secret.Object["stringData"] = map[string]interface{}{"password": "secret"}
+		children = append(children, secret)
+	}
+
+	observed := int64(0)
+	for _, c := range request.Children {
+		observed += int64(len(c))
+	}
+
+	return &hook.SyncResponse{
+		Status:   map[string]interface{}{"observedChildren": observed},
+		Children: children,
+	}, nil
+}
+
+func newTestParent() *unstructured.Unstructured {
+	parent := &unstructured.Unstructured{}
+	parent.SetAPIVersion("ctl.isla.solutions/v1")
+	parent.SetKind("AppDB")
+	parent.SetNamespace("default")
+	parent.SetName("db1")
+	parent.SetUID(types.UID("uid-db1"))
+	parent.SetGeneration(1)
+	parent.Object["spec"] = map[string]interface{}{"value": "v1"}
+	return parent
+}
+
+func newTestChild(resource ChildResource, name string, parent *unstructured.Unstructured) *unstructured.Unstructured {
+	child := &unstructured.Unstructured{}
+	child.SetAPIVersion(resource.APIVersion)
+	child.SetKind(resource.Kind)
+	child.SetNamespace(parent.GetNamespace())
+	child.SetName(name)
+	child.SetUID(types.UID("uid-" + name))
+	setControllerRef(child, parent)
+	return child
+}
+
+func setupReconcilerTest(t *testing.T, objs ...runtime.Object) *reconciler {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	kubeClient := kubev1.NewClientForInterface(dynamicClient, 0, kubev1.AppDBResource, testConfigMaps.GroupVersionResource(), testSecrets.GroupVersionResource())
+
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	if err := kubeClient.Start(stopCh); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	return &reconciler{
+		Controller: Controller{
+			Name:           "test",
+			ParentResource: kubev1.AppDBResource,
+			ChildResources: []ChildResource{testConfigMaps, testSecrets},
+			Sync:           testSync,
+		},
+		kubeClient: kubeClient,
+		config:     &Config{ResyncPeriod: DEFAULT_RESYNC_PERIOD},
+	}
+}
+
+// hookSync posts the parent and children to the sync hook like metacontroller and returns the response.
+func hookSync(t *testing.T, parent *unstructured.Unstructured, children map[string]map[string]*unstructured.Unstructured) hook.SyncResponse {
+	data, err := json.Marshal(hook.SyncRequest{Parent: parent, Children: children})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	hook.Handler("AppDB", testSync)(rec, httptest.NewRequest("POST", "/sync/appdb", bytes.NewReader(data)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Unexpected status from sync hook: %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp hook.SyncResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return resp
+}
+
+// clusterChildren returns the children of the parent in the fake cluster by kind and name, read from the API rather than the informer cache.
+func clusterChildren(t *testing.T, r *reconciler, parent *unstructured.Unstructured) map[string]*unstructured.Unstructured {
+	children := make(map[string]*unstructured.Unstructured, 0)
+	for _, resource := range r.ChildResources {
+		list, err := r.kubeClient.Resource(resource.GroupVersionResource()).Namespace(parent.GetNamespace()).List(metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i := range list.Items {
+			child := &list.Items[i]
+			if ref := metav1.GetControllerOf(child); ref != nil && ref.UID == parent.GetUID() {
+				children[resource.Kind+"/"+child.GetName()] = child
+			}
+		}
+	}
+	return children
+}
+
+// waitForCache waits until the informer cache has the children in the cluster, so that the next reconcile observes them.
+func waitForCache(t *testing.T, r *reconciler, parent *unstructured.Unstructured) map[string]map[string]*unstructured.Unstructured {
+	want := clusterChildren(t, r, parent)
+	for i := 0; i < 100; i++ {
+		observed, err := r.observedChildren(parent)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got := make(map[string]*unstructured.Unstructured, 0)
+		for _, resource := range r.ChildResources {
+			for name, child := range observed[resource.Key()] {
+				got[resource.Kind+"/"+name] = child
+			}
+		}
+		if reflect.DeepEqual(got, want) {
+			return observed
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Informer cache did not observe the children: %v", want)
+	return nil
+}
+
+// assertSameChildren compares the children in the cluster with the children of the hook response, the fields that metacontroller sets are ignored.
+func assertSameChildren(t *testing.T, round string, got map[string]*unstructured.Unstructured, desired []*unstructured.Unstructured) {
+	gotNames := make([]string, 0)
+	for name := range got {
+		gotNames = append(gotNames, name)
+	}
+	wantNames := make([]string, 0)
+	for _, child := range desired {
+		wantNames = append(wantNames, child.GetKind()+"/"+child.GetName())
+	}
+	sort.Strings(gotNames)
+	sort.Strings(wantNames)
+	if reflect.DeepEqual(gotNames, wantNames) == false {
+		t.Fatalf("%s: expected children %v, got: %v", round, wantNames, gotNames)
+	}
+
+	for _, want := range desired {
+		child := got[want.GetKind()+"/"+want.GetName()]
+		for _, field := range []string{"data", "stringData"} {
+			if reflect.DeepEqual(child.Object[field], want.Object[field]) == false {
+				t.Errorf("%s: %s/%s: expected %s %v, got: %v", round, want.GetKind(), want.GetName(), field, want.Object[field], child.Object[field])
+			}
+		}
+		if reflect.DeepEqual(child.GetLabels(), want.GetLabels()) == false {
+			t.Errorf("%s: %s/%s: expected labels %v, got: %v", round, want.GetKind(), want.GetName(), want.GetLabels(), child.GetLabels())
+		}
+	}
+}
+
+func TestReconcileMatchesHook(t *testing.T) {
+	parent := newTestParent()
+	// Owned by the parent but no longer desired, both modes delete it.
+	stale := newTestChild(testConfigMaps, "db1-old", parent)
+	r := setupReconcilerTest(t, parent, stale)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "db1"}}
+
+	// First sync: the stale ConfigMap is observed.
+	resp := hookSync(t, parent, map[string]map[string]*unstructured.Unstructured{
+		testConfigMaps.Key(): {"db1-old": stale},
+		testSecrets.Key():    {},
+	})
+
+	result, err := r.Reconcile(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.RequeueAfter != DEFAULT_RESYNC_PERIOD {
+		t.Errorf("Expected requeue after the resync period, got: %v", result.RequeueAfter)
+	}
+	assertSameChildren(t, "first sync", clusterChildren(t, r, parent), resp.Children)
+
+	updated, err := r.kubeClient.Resource(kubev1.AppDBResource).Namespace("default").Get("db1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for k, v := range resp.Status {
+		// The hook response is decoded with float64 numbers.
+		if got := updated.Object["status"].(map[string]interface{})[k]; fmt.Sprint(got) != fmt.Sprint(v) {
+			t.Errorf("Expected status %s %v, got: %v", k, v, got)
+		}
+	}
+
+	// Second sync: the ConfigMap created by the first sync is observed and the Secret is added.
+	resp = hookSync(t, parent, waitForCache(t, r, parent))
+	if len(resp.Children) != 2 {
+		t.Fatalf("Expected the hook to add the Secret, got: %v", resp.Children)
+	}
+
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertSameChildren(t, "second sync", clusterChildren(t, r, parent), resp.Children)
+}
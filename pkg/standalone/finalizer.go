@@ -0,0 +1,45 @@
+package standalone
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// finalizerName is the finalizer of the controller, same as the finalizer metacontroller adds for the finalize hook of the CompositeController with the name.
+func (r *reconciler) finalizerName() string {
+	return fmt.Sprintf("metacontroller.app/compositecontroller-%s", r.Name)
+}
+
+func hasFinalizer(obj *unstructured.Unstructured, name string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addFinalizer adds the finalizer of the controller to the parent.
+func (r *reconciler) addFinalizer(parent *unstructured.Unstructured) error {
+	updated := parent.DeepCopy()
+	updated.SetFinalizers(append(updated.GetFinalizers(), r.finalizerName()))
+	_, err := r.kubeClient.Resource(r.ParentResource).Namespace(parent.GetNamespace()).Update(updated, metav1.UpdateOptions{})
+	return err
+}
+
+// removeFinalizer removes the finalizer of the controller from the parent.
+func (r *reconciler) removeFinalizer(parent *unstructured.Unstructured) error {
+	finalizers := make([]string, 0)
+	for _, f := range parent.GetFinalizers() {
+		if f != r.finalizerName() {
+			finalizers = append(finalizers, f)
+		}
+	}
+
+	updated := parent.DeepCopy()
+	updated.SetFinalizers(finalizers)
+	_, err := r.kubeClient.Resource(r.ParentResource).Namespace(parent.GetNamespace()).Update(updated, metav1.UpdateOptions{})
+	return err
+}
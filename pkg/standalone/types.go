@@ -0,0 +1,44 @@
+package standalone
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// UpdateStrategy is how changes to an existing child are applied, same as the updateStrategy.method of the CompositeController child resources.
+type UpdateStrategy string
+
+const (
+	// UpdateStrategyOnDelete leaves existing children unchanged, the default.
+	UpdateStrategyOnDelete UpdateStrategy = "OnDelete"
+	// UpdateStrategyInPlace updates existing children with the desired fields.
+	UpdateStrategyInPlace UpdateStrategy = "InPlace"
+)
+
+// ChildResource is a resource type that the parent owns.
+type ChildResource struct {
+	APIVersion     string
+	Kind           string
+	Resource       string
+	UpdateStrategy UpdateStrategy
+}
+
+// GroupVersionResource returns the resource for the dynamic client.
+func (r ChildResource) GroupVersionResource() schema.GroupVersionResource {
+	gv, _ := schema.ParseGroupVersion(r.APIVersion)
+	return gv.WithResource(r.Resource)
+}
+
+// Key returns the key of the children in the sync request, <Kind>.<apiVersion> like metacontroller.
+func (r ChildResource) Key() string {
+	return fmt.Sprintf("%s.%s", r.Kind, r.APIVersion)
+}
+
+// Watch enqueues the parents returned by Map for changes to objects of the resource.
+type Watch struct {
+	Resource schema.GroupVersionResource
+	Map      func(obj *unstructured.Unstructured) []types.NamespacedName
+}
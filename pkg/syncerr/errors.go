@@ -0,0 +1,71 @@
+// Package syncerr classifies the errors returned by the sync of a parent, so that the webhook handlers can choose between letting metacontroller retry and reporting the error on the status.
+package syncerr
+
+import (
+	"fmt"
+)
+
+// Reason is the class of a sync error, it is also used as the reason of the status condition reporting the error.
+type Reason string
+
+const (
+	// ReasonTransient errors are expected to go away on retry, like a failed API call.
+	ReasonTransient Reason = "SyncTransientError"
+	// ReasonPermanent errors will not go away until the parent or the operator configuration changes.
+	ReasonPermanent Reason = "SyncPermanentError"
+	// ReasonInvalidSpec errors are permanent errors caused by the spec of the parent.
+	ReasonInvalidSpec Reason = "InvalidSpec"
+)
+
+// Error is a sync error with its class.
+type Error struct {
+	Reason Reason
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Transient wraps err as a transient sync error.
+func Transient(err error) error {
+	return &Error{Reason: ReasonTransient, Err: err}
+}
+
+// Transientf returns a transient sync error with the formatted message.
+func Transientf(msgfmt string, args ...interface{}) error {
+	return Transient(fmt.Errorf(msgfmt, args...))
+}
+
+// Permanent wraps err as a permanent sync error.
+func Permanent(err error) error {
+	return &Error{Reason: ReasonPermanent, Err: err}
+}
+
+// Permanentf returns a permanent sync error with the formatted message.
+func Permanentf(msgfmt string, args ...interface{}) error {
+	return Permanent(fmt.Errorf(msgfmt, args...))
+}
+
+// InvalidSpec wraps err as an invalid spec sync error.
+func InvalidSpec(err error) error {
+	return &Error{Reason: ReasonInvalidSpec, Err: err}
+}
+
+// InvalidSpecf returns an invalid spec sync error with the formatted message.
+func InvalidSpecf(msgfmt string, args ...interface{}) error {
+	return InvalidSpec(fmt.Errorf(msgfmt, args...))
+}
+
+// ReasonOf returns the class of err, errors that were not classified are treated as transient.
+func ReasonOf(err error) Reason {
+	if e, ok := err.(*Error); ok == true {
+		return e.Reason
+	}
+	return ReasonTransient
+}
+
+// IsTransient returns true if the sync should be retried by metacontroller.
+func IsTransient(err error) bool {
+	return ReasonOf(err) == ReasonTransient
+}
@@ -0,0 +1,37 @@
+package syncerr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReasonOf(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantReason    Reason
+		wantTransient bool
+	}{
+		{"transient", Transientf("API unavailable"), ReasonTransient, true},
+		{"permanent", Permanentf("driver disabled"), ReasonPermanent, false},
+		{"invalid spec", InvalidSpecf("missing spec.dbName"), ReasonInvalidSpec, false},
+		{"wrapped permanent", Permanent(errors.New("wrapped")), ReasonPermanent, false},
+		{"unclassified", errors.New("connection refused"), ReasonTransient, true},
+	}
+
+	for _, tc := range tests {
+		if reason := ReasonOf(tc.err); reason != tc.wantReason {
+			t.Errorf("%s: expected reason %s, got: %s", tc.name, tc.wantReason, reason)
+		}
+		if transient := IsTransient(tc.err); transient != tc.wantTransient {
+			t.Errorf("%s: expected transient %t, got: %t", tc.name, tc.wantTransient, transient)
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := InvalidSpecf("spec.%s is required", "dbName")
+	if err.Error() != "spec.dbName is required" {
+		t.Errorf("Expected the message of the wrapped error, got: %s", err.Error())
+	}
+}
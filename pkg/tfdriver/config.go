@@ -3,19 +3,20 @@ package tfdriver
 import (
 	"fmt"
 	"log"
-	"os"
-	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
 const (
 	DEFAULT_TF_PROVIDER_SECRET = "tf-provider-google"
+	DEFAULT_TF_BACKEND_PREFIX  = "terraform"
+	DEFAULT_TF_MAX_ATTEMPTS    = 4
 )
 
-// TerraformDriverConfig is the Terraform driver config
+// TerraformDriverConfig is the Terraform driver config, the settings are read by the operator config.
 type TerraformDriverConfig struct {
 	Image                      string
+	IAMUsersImage              string
 	ImagePullPolicy            corev1.PullPolicy
 	BackendBucket              string
 	BackendPrefix              string
@@ -23,57 +24,53 @@ type TerraformDriverConfig struct {
 	GoogleProviderConfigSecret string
 }
 
+// LoadAndValidate validates the settings read by the operator config and derives the backend bucket from the project when it is not set.
 func (c *TerraformDriverConfig) LoadAndValidate(project string) error {
 
-	// TF_IMAGE is optional
-	c.Image, _ = os.LookupEnv("TF_IMAGE")
+	// TF_BACKEND_BUCKET is optional, derived from the project when not set
+	if c.BackendBucket == "" {
+		if project != "" {
+			// Create bucket name from project name.
+			c.BackendBucket = fmt.Sprintf("%s-appdb-operator", project)
+			log.Printf("[INFO] No TF_BACKEND_BUCKET given, using canonical bucket name: %s", c.BackendBucket)
+		} else {
+			log.Printf("[WARN] No TF_BACKEND_BUCKET or project given, the cloudSQLTerraform driver is disabled")
+		}
+	}
 
-	// TF_IMAGE_PULL_POLICY is optional
-	if pullPolicy, ok := os.LookupEnv("TF_IMAGE_PULL_POLICY"); ok == true {
-		c.ImagePullPolicy = corev1.PullPolicy(pullPolicy)
-	} else {
-		c.ImagePullPolicy = corev1.PullIfNotPresent
+	if c.BackendPrefix == "" {
+		return fmt.Errorf("Missing TF_BACKEND_PREFIX")
 	}
 
-	// TF_BACKEND_BUCKET is required
-	if backendBucket, ok := os.LookupEnv("TF_BACKEND_BUCKET"); ok == true {
-		c.BackendBucket = backendBucket
-	} else {
-		// Create bucket name from project name.
-		c.BackendBucket = fmt.Sprintf("%s-appdb-operator", project)
-		log.Printf("[INFO] No TF_BACKEND_BUCKET given, using canonical bucket name: %s", c.BackendBucket)
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("Invalid number for TF_MAX_ATTEMPTS: %d, must be positive integer", c.MaxAttempts)
 	}
 
-	// TF_BACKEND_PREFIX is required
-	if backendPrefix, ok := os.LookupEnv("TF_BACKEND_PREFIX"); ok == true {
-		c.BackendPrefix = backendPrefix
-	} else {
-		// Use default prefix.
-		c.BackendPrefix = "terraform"
-		log.Printf("[INFO] No TF_BACKEND_PREFIX given, using default prefix of: %s", c.BackendPrefix)
+	if c.GoogleProviderConfigSecret == "" {
+		return fmt.Errorf("Missing TF_GOOGLE_PROVIDER_SECRET")
 	}
 
-	if maxAttempts, ok := os.LookupEnv("TF_MAX_ATTEMPTS"); ok == true {
-		i, err := strconv.Atoi(maxAttempts)
-		if err != nil {
-			return fmt.Errorf("Invalid number for TF_MAX_ATTEMPTS: %s, must be positive integer", maxAttempts)
-		}
-		if i <= 0 {
-			return fmt.Errorf("Invalid number for TF_MAX_ATTEMPTS: %s, must be positive integer", maxAttempts)
-		}
+	return nil
+}
 
-		c.MaxAttempts = i
-	} else {
-		c.MaxAttempts = 4
-		log.Printf("[INFO] No TF_MAX_ATTEMPTS given, using default count of: %d", c.MaxAttempts)
-	}
+// Enabled returns true if the driver can be used, the backend bucket is required.
+func (c *TerraformDriverConfig) Enabled() bool {
+	return c.BackendBucket != ""
+}
 
-	if googleConfigSecret, ok := os.LookupEnv("TF_GOOGLE_PROVIDER_SECRET"); ok == true {
-		c.GoogleProviderConfigSecret = googleConfigSecret
-	} else {
-		c.GoogleProviderConfigSecret = DEFAULT_TF_PROVIDER_SECRET
-		log.Printf("[INFO] No TF_GOOGLE_PROVIDER_SECRET given, using default: %s", c.GoogleProviderConfigSecret)
+// Validate returns an error if a required setting is missing, it is used by the readiness check.
+func (c *TerraformDriverConfig) Validate() error {
+	if c.BackendBucket == "" {
+		return fmt.Errorf("Missing Terraform backend bucket")
+	}
+	if c.BackendPrefix == "" {
+		return fmt.Errorf("Missing Terraform backend prefix")
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("Invalid Terraform max attempts: %d, must be positive integer", c.MaxAttempts)
+	}
+	if c.GoogleProviderConfigSecret == "" {
+		return fmt.Errorf("Missing Terraform Google provider secret")
 	}
-
 	return nil
 }
@@ -0,0 +1,14 @@
+package tfdriver
+
+import (
+	"io/ioutil"
+)
+
+// LoadManifest returns the embedded Terraform manifest at srcPath.
+func LoadManifest(srcPath string) (string, error) {
+	manifest, err := ioutil.ReadFile(srcPath)
+	if err != nil {
+		return "", err
+	}
+	return string(manifest), nil
+}
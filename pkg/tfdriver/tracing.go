@@ -0,0 +1,21 @@
+package tfdriver
+
+import (
+	"context"
+	"time"
+
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
+	"github.com/danisla/appdb-operator/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RecordSpan records the run of the TerraformApply or TerraformPlan pod as a span of the trace of the sync that created it.
+// Nothing is recorded until the pod has finished.
+func RecordSpan(ctx context.Context, kind string, tf tfv1.Terraform) {
+	finishedAt, err := time.Parse(time.RFC3339, tf.Status.FinishedAt)
+	if err != nil {
+		return
+	}
+	tracing.RecordSpan(tracing.ContextFromAnnotations(ctx, tf.Annotations), kind, tf.GetCreationTimestamp().Time, finishedAt,
+		append(tracing.ObjectAttributes(kind, tf.GetNamespace(), tf.GetName()), attribute.String("terraform.pod_status", string(tf.Status.PodStatus)))...)
+}
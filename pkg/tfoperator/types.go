@@ -0,0 +1,75 @@
+// Package tfoperator holds the subset of the terraform-operator API types (github.com/danisla/terraform-operator/pkg/types, release 0.3.6)
+// that the AppDB operators create and read. The terraform-operator releases are not tagged as semantic versions, so it can't be required as a Go module.
+package tfoperator
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodStatus is the status of the Terraform pod of a TerraformPlan, TerraformApply or TerraformDestroy.
+type PodStatus string
+
+const (
+	PodStatusPassed PodStatus = "COMPLETED"
+	PodStatusFailed PodStatus = "FAILED"
+)
+
+// Terraform is the TerraformPlan, TerraformApply and TerraformDestroy custom resource structure.
+type Terraform struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TerraformSpec           `json:"spec,omitempty"`
+	SpecFrom          TerraformSpecFrom       `json:"specFrom,omitempty"`
+	Status            TerraformOperatorStatus `json:"status"`
+}
+
+// TerraformSpecFrom copies the spec from another Terraform resource.
+type TerraformSpecFrom struct {
+	TFApply string `json:"tfapply,omitempty"`
+}
+
+// TerraformSpec is the spec of a Terraform resource.
+type TerraformSpec struct {
+	Image           string                                 `json:"image,omitempty"`
+	ImagePullPolicy corev1.PullPolicy                      `json:"imagePullPolicy,omitempty"`
+	BackendBucket   string                                 `json:"backendBucket,omitempty"`
+	BackendPrefix   string                                 `json:"backendPrefix,omitempty"`
+	ProviderConfig  map[string]TerraformSpecProviderConfig `json:"providerConfig,omitempty"`
+	Sources         []TerraformConfigSource                `json:"sources,omitempty"`
+	TFVars          map[string]string                      `json:"tfvars,omitempty"`
+	MaxAttempts     int                                    `json:"maxAttempts,omitempty"`
+}
+
+// TerraformSpecProviderConfig names the secret with the credentials of a provider.
+type TerraformSpecProviderConfig struct {
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// TerraformConfigSource is a source of Terraform configuration.
+type TerraformConfigSource struct {
+	Embedded string `json:"embedded,omitempty"`
+}
+
+// TerraformOperatorStatus is the status of a Terraform resource.
+type TerraformOperatorStatus struct {
+	PodName    string                        `json:"podName,omitempty"`
+	PodStatus  PodStatus                     `json:"podStatus,omitempty"`
+	StartedAt  string                        `json:"startedAt,omitempty"`
+	FinishedAt string                        `json:"finishedAt,omitempty"`
+	TFOutput   map[string]TerraformOutputVar `json:"outputs,omitempty"`
+	TFPlanDiff *TerraformPlanDiff            `json:"planDiff,omitempty"`
+}
+
+// TerraformOutputVar is a Terraform output variable.
+type TerraformOutputVar struct {
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// TerraformPlanDiff is the number of resources changed by a TerraformPlan.
+type TerraformPlanDiff struct {
+	Added     int `json:"added"`
+	Changed   int `json:"changed"`
+	Destroyed int `json:"destroyed"`
+}
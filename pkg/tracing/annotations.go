@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// TraceParentAnnotation is the W3C traceparent of the sync that created a child resource.
+	TraceParentAnnotation = "ctl.isla.solutions/traceparent"
+	// TraceStateAnnotation is the W3C tracestate of the sync that created a child resource.
+	TraceStateAnnotation = "ctl.isla.solutions/tracestate"
+)
+
+var annotationKeys = map[string]string{
+	"traceparent": TraceParentAnnotation,
+	"tracestate":  TraceStateAnnotation,
+}
+
+// annotationCarrier maps the W3C trace context headers to annotations.
+type annotationCarrier map[string]string
+
+func (c annotationCarrier) Get(key string) string {
+	return c[annotationKeys[key]]
+}
+
+func (c annotationCarrier) Set(key, value string) {
+	if annotation, ok := annotationKeys[key]; ok == true {
+		c[annotation] = value
+	}
+}
+
+func (c annotationCarrier) Keys() []string {
+	keys := make([]string, 0, len(annotationKeys))
+	for k := range annotationKeys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// InjectAnnotations adds the trace context of the span in ctx to the annotations of a new child resource.
+// Only use it when the child is created, the annotations of an existing child must not change on every sync, see CopyAnnotations.
+func InjectAnnotations(ctx context.Context, annotations map[string]string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string, 0)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, annotationCarrier(annotations))
+	return annotations
+}
+
+// CopyAnnotations copies the trace context annotations of the existing child resource to the annotations of the desired child.
+func CopyAnnotations(from map[string]string, to map[string]string) map[string]string {
+	for _, annotation := range annotationKeys {
+		if v, ok := from[annotation]; ok == true {
+			if to == nil {
+				to = make(map[string]string, 0)
+			}
+			to[annotation] = v
+		}
+	}
+	return to
+}
+
+// ContextFromAnnotations returns a copy of ctx with the trace context from the annotations of a child resource.
+func ContextFromAnnotations(ctx context.Context, annotations map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, annotationCarrier(annotations))
+}
+
+// ContextFromRequest returns a copy of ctx with the trace context from the headers of the webhook request, if any.
+func ContextFromRequest(ctx context.Context, r *http.Request) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/danisla/appdb-operator"
+
+// Init exports spans to the OTLP gRPC endpoint in OTEL_EXPORTER_OTLP_ENDPOINT, tracing is disabled when it is not set.
+// The exporter also reads the other OTEL_EXPORTER_OTLP_* environment variables, like OTEL_EXPORTER_OTLP_INSECURE.
+// The returned function flushes the pending spans and must be called before exiting.
+func Init(serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if _, ok := os.LookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"); ok == false {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return setTracerProvider(serviceName, sdktrace.WithBatcher(exporter)), nil
+}
+
+// InitWithExporter sets the global tracer provider to export spans with the exporter as soon as they end.
+// It is meant for tests with the in-memory exporter of the go.opentelemetry.io/otel/sdk/trace/tracetest package.
+func InitWithExporter(serviceName string, exporter sdktrace.SpanExporter) func(context.Context) error {
+	return setTracerProvider(serviceName, sdktrace.WithSyncer(exporter))
+}
+
+func setTracerProvider(serviceName string, exporterOpt sdktrace.TracerProviderOption) func(context.Context) error {
+	provider := sdktrace.NewTracerProvider(
+		exporterOpt,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown
+}
+
+// Start starts a span as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// RecordSpan records a span that already happened, like the run of a TerraformApply pod or a Job, from start to end.
+// The span is a child of the span in ctx, use ContextFromAnnotations to make it a child of the sync that created the resource.
+func RecordSpan(ctx context.Context, name string, start, end time.Time, attrs ...attribute.KeyValue) {
+	if start.IsZero() || end.Before(start) {
+		return
+	}
+	_, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+	span.End(trace.WithTimestamp(end))
+}
+
+// ObjectAttributes returns the span attributes for a resource.
+func ObjectAttributes(kind, namespace, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("k8s.kind", kind),
+		attribute.String("k8s.namespace.name", namespace),
+		attribute.String("k8s.object.name", name),
+	}
+}
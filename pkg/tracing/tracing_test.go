@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := InitWithExporter("appdb-operator-test", exporter)
+	t.Cleanup(func() {
+		shutdown(context.Background())
+	})
+	return exporter
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("Span %s not found in %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, kv attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == kv {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStartEnd(t *testing.T) {
+	exporter := newTestExporter(t)
+
+	ctx, parent := Start(context.Background(), "sync AppDB", ObjectAttributes("AppDB", "default", "db1")...)
+	_, child := Start(ctx, "vault DeleteDatabaseRole")
+	End(child, errors.New("permission denied"))
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got: %d", len(spans))
+	}
+
+	parentSpan := findSpan(t, spans, "sync AppDB")
+	childSpan := findSpan(t, spans, "vault DeleteDatabaseRole")
+
+	if childSpan.Parent.SpanID() != parentSpan.SpanContext.SpanID() || childSpan.SpanContext.TraceID() != parentSpan.SpanContext.TraceID() {
+		t.Errorf("Expected vault span to be a child of the sync span")
+	}
+	if hasAttribute(parentSpan.Attributes, attribute.String("k8s.object.name", "db1")) == false {
+		t.Errorf("Expected object attributes on sync span, got: %v", parentSpan.Attributes)
+	}
+	if parentSpan.Status.Code != codes.Unset {
+		t.Errorf("Expected unset status on sync span, got: %v", parentSpan.Status)
+	}
+	if childSpan.Status.Code != codes.Error || childSpan.Status.Description != "permission denied" {
+		t.Errorf("Expected error status on vault span, got: %v", childSpan.Status)
+	}
+	if len(childSpan.Events) != 1 || childSpan.Events[0].Name != "exception" {
+		t.Errorf("Expected recorded error event on vault span, got: %v", childSpan.Events)
+	}
+	if hasAttribute(parentSpan.Resource.Attributes(), attribute.String("service.name", "appdb-operator-test")) == false {
+		t.Errorf("Expected service name resource, got: %v", parentSpan.Resource.Attributes())
+	}
+}
+
+func TestRecordSpanFromAnnotations(t *testing.T) {
+	exporter := newTestExporter(t)
+
+	ctx, sync := Start(context.Background(), "sync AppDBInstance")
+	annotations := InjectAnnotations(ctx, nil)
+	End(sync, nil)
+
+	if annotations[TraceParentAnnotation] == "" {
+		t.Fatalf("Expected %s annotation, got: %v", TraceParentAnnotation, annotations)
+	}
+
+	// The existing child keeps the annotations of the sync that created it.
+	desired := CopyAnnotations(annotations, map[string]string{"app": "test"})
+	if desired[TraceParentAnnotation] != annotations[TraceParentAnnotation] || desired["app"] != "test" {
+		t.Errorf("Expected copied trace annotations, got: %v", desired)
+	}
+
+	start := time.Now().Add(-time.Minute)
+	end := time.Now()
+	RecordSpan(ContextFromAnnotations(context.Background(), annotations), "TerraformApply", start, end)
+
+	// Not finished, nothing is recorded.
+	RecordSpan(context.Background(), "TerraformPlan", time.Time{}, end)
+	RecordSpan(context.Background(), "TerraformPlan", end, start)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got: %d", len(spans))
+	}
+
+	syncSpan := findSpan(t, spans, "sync AppDBInstance")
+	applySpan := findSpan(t, spans, "TerraformApply")
+
+	if applySpan.Parent.SpanID() != syncSpan.SpanContext.SpanID() || applySpan.SpanContext.TraceID() != syncSpan.SpanContext.TraceID() {
+		t.Errorf("Expected TerraformApply span to be a child of the sync that created it")
+	}
+	if applySpan.StartTime.Equal(start) == false || applySpan.EndTime.Equal(end) == false {
+		t.Errorf("Expected span from %v to %v, got: %v to %v", start, end, applySpan.StartTime, applySpan.EndTime)
+	}
+}
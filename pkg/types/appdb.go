@@ -1,8 +1,7 @@
 package types
 
 import (
-	"fmt"
-	"log"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -10,10 +9,16 @@ import (
 // AppDBOperatorStatus is the status structure for the custom resource
 type AppDBOperatorStatus struct {
 	Provisioning       ProvisioningStatus     `json:"provisioning,omitempty"`
+	DBHost             string                 `json:"dbHost,omitempty"`
+	AppDBInstance      string                 `json:"appDBInstance,omitempty"`
 	AppDBInstanceSig   string                 `json:"appDBInstanceSig,omitempty"`
+	Placement          *AppDBPlacementStatus  `json:"placement,omitempty"`
 	CloudSQLDB         *AppDBCloudSQLDBStatus `json:"cloudSQLDB,omitempty"`
+	Vault              *AppDBVaultStatus      `json:"vault,omitempty"`
 	CredentialsSecrets map[string]string      `json:"credentialsSecrets,omitempty"`
 	Conditions         []AppDBCondition       `json:"conditions,omitempty"`
+	// ObservedGeneration is set by metacontroller after each sync.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // AppDBCondition defines the format for a status condition element.
@@ -33,6 +38,25 @@ type AppDBCloudSQLDBStatus struct {
 	TFApplySig     string `json:"tfapplySig,omitempty"`
 }
 
+// AppDBVaultStatus is the status structure for the Vault dynamic credentials mode
+type AppDBVaultStatus struct {
+	ConnectionName string `json:"connectionName,omitempty"`
+	RoleName       string `json:"roleName,omitempty"`
+	RolePath       string `json:"rolePath,omitempty"`
+	ConfigSig      string `json:"configSig,omitempty"`
+}
+
+// AppDBPlacementStatus records the AppDBInstance selected for an AppDB using spec.instanceClassName.
+type AppDBPlacementStatus struct {
+	InstanceClassName string `json:"instanceClassName,omitempty"`
+	AppDBInstance     string `json:"appDBInstance,omitempty"`
+	SizeHint          int32  `json:"sizeHint,omitempty"`
+	// InstanceLoad is the load of the instance, including this AppDB, at the time of placement.
+	InstanceLoad int32 `json:"instanceLoad,omitempty"`
+	// Created is true when a new AppDBInstance was created for the AppDB.
+	Created bool `json:"created,omitempty"`
+}
+
 // AppDBConditionType is a valid value for AppDBCondition.Type
 type AppDBConditionType string
 
@@ -46,10 +70,14 @@ const (
 	ConditionTypeDBCreateComplete AppDBConditionType = "DBCreateComplete"
 	// ConditionTypeSnapshotLoadComplete is True when the Job for loading SQL data has been created and is complete.
 	ConditionTypeSnapshotLoadComplete AppDBConditionType = "SnapshotLoadComplete"
+	// ConditionTypeVaultRoleConfigured is True when the Vault database secrets engine connection and role have been written.
+	ConditionTypeVaultRoleConfigured AppDBConditionType = "VaultRoleConfigured"
 	// ConditionTypeCredentialsSecretCreated is True when the secret containing the database credentials and info has been created.
 	ConditionTypeCredentialsSecretCreated AppDBConditionType = "CredentialsSecretCreated"
 	// ConditionTypeAppDBReady means that all prior conditions are Ready
 	ConditionTypeAppDBReady AppDBConditionType = "Ready"
+	// ConditionTypeSynced is True when the last sync of an AppDB or AppDBInstance succeeded, the reason is the class of the error otherwise.
+	ConditionTypeSynced AppDBConditionType = "Synced"
 )
 
 type ConditionStatus string
@@ -60,23 +88,130 @@ const (
 	ConditionUnknown ConditionStatus = "Unknown"
 )
 
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=appdbs,shortName=appdb
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".status.appDBInstance"
+// +kubebuilder:printcolumn:name="Provisioning",type="string",JSONPath=".status.provisioning"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="DBHost",type="string",JSONPath=".status.dbHost"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
 // AppDB is the custom resource definition structure.
 type AppDB struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              AppDBSpec           `json:"spec,omitempty"`
-	Status            AppDBOperatorStatus `json:"status"`
+	Spec              AppDBSpec `json:"spec,omitempty"`
+	// +optional
+	Status AppDBOperatorStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// AppDBList is a list of AppDB resources.
+type AppDBList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppDB `json:"items"`
 }
 
-// Log is a conventional log method to print the parent name and kind before the log message.
-func (parent *AppDB) Log(level, msgfmt string, fmtargs ...interface{}) {
-	log.Printf("[%s][%s][%s] %s", level, parent.Kind, parent.Name, fmt.Sprintf(msgfmt, fmtargs...))
+// GetAppDBInstanceRef returns the namespace and name of the AppDBInstance.
+// spec.appDBInstance is either <name> for an instance in the namespace of the AppDB, or <namespace>/<name>.
+// When spec.instanceClassName is used, the instance selected by the operator is read from status.appDBInstance.
+func (parent *AppDB) GetAppDBInstanceRef() (string, string) {
+	ref := parent.Spec.AppDBInstance
+	if ref == "" {
+		ref = parent.Status.AppDBInstance
+	}
+	return ParseAppDBInstanceRef(ref, parent.GetNamespace())
 }
 
+// ParseAppDBInstanceRef splits a <name> or <namespace>/<name> AppDBInstance reference, defaulting to the given namespace.
+func ParseAppDBInstanceRef(ref, namespace string) (string, string) {
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return namespace, ref
+}
+
+// AppDBNameLabel is the pod label used to select the AppDB for Cloud SQL proxy sidecar injection.
+const AppDBNameLabel = "appdb.ctl.isla.solutions/name"
+
 // AppDBSpec is the top level structure of the spec body
 type AppDBSpec struct {
-	AppDBInstance string   `json:"appDBInstance,omitempty"`
-	DBName        string   `json:"dbName,omitempty"`
-	Users         []string `json:"users,omitempty"`
-	LoadURL       string   `json:"loadURL,omitempty"`
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	AppDBInstance string `json:"appDBInstance,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	InstanceClassName string `json:"instanceClassName,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	SizeHint int32 `json:"sizeHint,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	DBName      string                `json:"dbName,omitempty"`
+	Users       []string              `json:"users,omitempty"`
+	IAMUsers    []AppDBIAMUser        `json:"iamUsers,omitempty"`
+	LoadURL     string                `json:"loadURL,omitempty"`
+	Credentials *AppDBCredentialsSpec `json:"credentials,omitempty"`
+}
+
+// AppDBUserType represents the string mapping to the possible types of IAM users.
+// +kubebuilder:validation:Enum=iamServiceAccount
+type AppDBUserType string
+
+const (
+	// AppDBUserTypeIAMServiceAccount is a Cloud SQL IAM database user mapped to a Google service account.
+	AppDBUserTypeIAMServiceAccount AppDBUserType = "iamServiceAccount"
+)
+
+// AppDBIAMUser is a database user that authenticates with IAM instead of a password.
+type AppDBIAMUser struct {
+	Type AppDBUserType `json:"type,omitempty"`
+	// +kubebuilder:validation:Pattern=`^.+@.+\.gserviceaccount\.com$`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// CredentialsMode represents the string mapping to the possible spec.credentials.mode values.
+// +kubebuilder:validation:Enum=static;vaultDynamic
+type CredentialsMode string
+
+const (
+	// CredentialsModeStatic creates a static password for each user and writes it to the credentials secret.
+	CredentialsModeStatic CredentialsMode = "static"
+	// CredentialsModeVaultDynamic configures a Vault database secrets engine role and writes the role path to the credentials secret.
+	CredentialsModeVaultDynamic CredentialsMode = "vaultDynamic"
+)
+
+// AppDBCredentialsSpec is the spec for how database credentials are issued to apps.
+type AppDBCredentialsSpec struct {
+	Mode  CredentialsMode            `json:"mode,omitempty"`
+	Vault *AppDBVaultCredentialsSpec `json:"vault,omitempty"`
+}
+
+// AppDBVaultCredentialsSpec is the spec for the Vault dynamic credentials mode.
+type AppDBVaultCredentialsSpec struct {
+	Mount string `json:"mount,omitempty"`
+	// RoleName is prefixed with the namespace and suffixed with the UID of the AppDB, defaults to appdb-<namespace>-<appdbinstance>-<name>.
+	RoleName   string `json:"roleName,omitempty"`
+	DefaultTTL string `json:"defaultTTL,omitempty"`
+	MaxTTL     string `json:"maxTTL,omitempty"`
+}
+
+// GetSizeHint returns the relative size of the database used for placement, defaulting to 1.
+func (spec *AppDBSpec) GetSizeHint() int32 {
+	if spec.SizeHint <= 0 {
+		return 1
+	}
+	return spec.SizeHint
+}
+
+// GetCredentialsMode returns the credentials mode, defaulting to CredentialsModeStatic.
+func (spec *AppDBSpec) GetCredentialsMode() CredentialsMode {
+	if spec.Credentials == nil || spec.Credentials.Mode == "" {
+		return CredentialsModeStatic
+	}
+	return spec.Credentials.Mode
 }
@@ -1,53 +1,107 @@
 package types
 
 import (
-	"fmt"
-	"log"
-
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=appdbinstances,shortName=appdbi
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Provisioning",type="string",JSONPath=".status.provisioning"
+// +kubebuilder:printcolumn:name="DBHost",type="string",JSONPath=".status.dbHost"
+// +kubebuilder:printcolumn:name="Port",type="integer",JSONPath=".status.dbPort"
+// +kubebuilder:printcolumn:name="Databases",type="integer",JSONPath=".status.databaseCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
 // AppDBInstance is the custom resource definition structure.
 type AppDBInstance struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              AppDBInstanceSpec           `json:"spec,omitempty"`
-	Status            AppDBInstanceOperatorStatus `json:"status"`
+	Spec              AppDBInstanceSpec `json:"spec,omitempty"`
+	// +optional
+	Status AppDBInstanceOperatorStatus `json:"status"`
 }
 
-// Log is a conventional log method to print the parent name and kind before the log message.
-func (parent *AppDBInstance) Log(level, msgfmt string, fmtargs ...interface{}) {
-	log.Printf("[%s][%s][%s] %s", level, parent.Kind, parent.Name, fmt.Sprintf(msgfmt, fmtargs...))
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// AppDBInstanceList is a list of AppDBInstance resources.
+type AppDBInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppDBInstance `json:"items"`
 }
 
 // AppDBInstanceOperatorStatus is the status structure for the custom resource
 type AppDBInstanceOperatorStatus struct {
-	Provisioning ProvisioningStatus           `json:"provisioning"`
-	DBHost       string                       `json:"dbHost"`
-	DBPort       int32                        `json:"dbPort"`
-	CloudSQL     *AppDBInstanceCloudSQLStatus `json:"cloudSQL"`
+	// +optional
+	Provisioning ProvisioningStatus `json:"provisioning"`
+	// +optional
+	DBHost string `json:"dbHost"`
+	// +optional
+	DBPort int32 `json:"dbPort"`
+	// +optional
+	DatabaseCount int32 `json:"databaseCount"`
+	// +optional
+	DatabaseLoad      int32  `json:"databaseLoad"`
+	RemainingCapacity *int32 `json:"remainingCapacity,omitempty"`
+	// +optional
+	// +nullable
+	CloudSQL *AppDBInstanceCloudSQLStatus `json:"cloudSQL"`
+	// Conditions reports the result of the last sync, see ConditionTypeSynced.
+	Conditions []AppDBCondition `json:"conditions,omitempty"`
+	// ObservedGeneration is set by metacontroller after each sync.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // AppDBInstanceCloudSQLStatus is the status structure for the CloudSQL driver
 type AppDBInstanceCloudSQLStatus struct {
-	InstanceName        string `json:"instanceName,omitempty"`
-	ServiceAccountEmail string `json:"serviceAccountEmail,omitempty"`
-	ConnectionName      string `json:"connectionName,omitempty"`
-	Port                int32  `json:"port,omitempty"`
-	ProxyService        string `json:"proxyService,omitempty"`
-	ProxySecret         string `json:"proxySecret,omitempty"`
-	TFApplyName         string `json:"tfapplyName,omitempty"`
-	TFApplyPodName      string `json:"tfapplyPodName,omitempty"`
-	TFApplySig          string `json:"tfapplySig,omitempty"`
-	TFPlanName          string `json:"tfplanName,omitempty"`
-	TFPlanPodName       string `json:"tfplanPodName,omitempty"`
-	TFPlanSig           string `json:"tfplanSig,omitempty"`
+	InstanceName        string                      `json:"instanceName,omitempty"`
+	ServiceAccountEmail string                      `json:"serviceAccountEmail,omitempty"`
+	ConnectionName      string                      `json:"connectionName,omitempty"`
+	Port                int32                       `json:"port,omitempty"`
+	PrivateIPAddress    string                      `json:"privateIPAddress,omitempty"`
+	ServerCACert        string                      `json:"serverCACert,omitempty"`
+	ProxyService        string                      `json:"proxyService,omitempty"`
+	ProxySecret         string                      `json:"proxySecret,omitempty"`
+	ProxyServiceAccount string                      `json:"proxyServiceAccount,omitempty"`
+	ProxyNetworkPolicy  string                      `json:"proxyNetworkPolicy,omitempty"`
+	ProxyRollout        *CloudSQLProxyRolloutStatus `json:"proxyRollout,omitempty"`
+	TFApplyName         string                      `json:"tfapplyName,omitempty"`
+	TFApplyPodName      string                      `json:"tfapplyPodName,omitempty"`
+	TFApplySig          string                      `json:"tfapplySig,omitempty"`
+	TFPlanName          string                      `json:"tfplanName,omitempty"`
+	TFPlanPodName       string                      `json:"tfplanPodName,omitempty"`
+	TFPlanSig           string                      `json:"tfplanSig,omitempty"`
+}
+
+// CloudSQLProxyRolloutStatus is the rollout status of the cloudsql proxy Deployment
+type CloudSQLProxyRolloutStatus struct {
+	State              ProvisioningStatus `json:"state,omitempty"`
+	Sig                string             `json:"sig,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Replicas           int32              `json:"replicas"`
+	UpdatedReplicas    int32              `json:"updatedReplicas"`
+	AvailableReplicas  int32              `json:"availableReplicas"`
 }
 
 // AppDBInstanceSpec is the top level structure of the spec body
 type AppDBInstanceSpec struct {
 	Driver AppDBDriver `json:"driver,omitempty"`
+
+	// AllowedNamespaces selects the namespaces of AppDBs that can reference the instance as <namespace>/<name>.
+	// When not set, only AppDBs in the namespace of the instance can use it. An empty selector allows all namespaces.
+	AllowedNamespaces *metav1.LabelSelector `json:"allowedNamespaces,omitempty"`
+
+	// MaxDatabases is the capacity of the instance, measured as the sum of spec.sizeHint of the AppDBs placed on it, 0 is unlimited.
+	// With the default size hint of 1, this is the maximum number of AppDBs.
+	// +kubebuilder:validation:Minimum=0
+	MaxDatabases int32 `json:"maxDatabases,omitempty"`
 }
 
 // AppDBDriver is the spec of the driver
@@ -57,13 +111,105 @@ type AppDBDriver struct {
 
 // AppDBCloudSQLDriver is the CloudSQL driver spec
 type AppDBCloudSQLTerraformDriver struct {
-	Params map[string]string `json:"params,omitempty"`
-	Proxy  CloudSQLProxySpec `json:"proxy,omitempty"`
+	Params       map[string]string        `json:"params,omitempty"`
+	Proxy        CloudSQLProxySpec        `json:"proxy,omitempty"`
+	Connectivity CloudSQLConnectivitySpec `json:"connectivity,omitempty"`
+}
+
+// CloudSQLConnectivitySpec is the spec for how apps connect to the Cloud SQL instance
+type CloudSQLConnectivitySpec struct {
+	Mode    CloudSQLConnectivityMode `json:"mode,omitempty"`
+	Network string                   `json:"network,omitempty"`
+	TLS     bool                     `json:"tls,omitempty"`
+}
+
+// CloudSQLConnectivityMode represents the string mapping to the possible connectivity.mode values.
+// +kubebuilder:validation:Enum=proxy;privateIP;privateIPProxy
+type CloudSQLConnectivityMode string
+
+const (
+	// CloudSQLConnectivityModeProxy connects through the Cloud SQL proxy to the public IP of the instance.
+	CloudSQLConnectivityModeProxy CloudSQLConnectivityMode = "proxy"
+	// CloudSQLConnectivityModePrivateIP connects directly to the private IP of the instance on the VPC network.
+	CloudSQLConnectivityModePrivateIP CloudSQLConnectivityMode = "privateIP"
+	// CloudSQLConnectivityModePrivateIPProxy connects through the Cloud SQL proxy to the private IP of the instance on the VPC network.
+	CloudSQLConnectivityModePrivateIPProxy CloudSQLConnectivityMode = "privateIPProxy"
+)
+
+// GetMode returns the connectivity mode, defaulting to CloudSQLConnectivityModeProxy.
+func (spec *CloudSQLConnectivitySpec) GetMode() CloudSQLConnectivityMode {
+	if spec.Mode == "" {
+		return CloudSQLConnectivityModeProxy
+	}
+	return spec.Mode
 }
 
 // CloudSQLProxySpec is the spec for a cloudsql proxy
 type CloudSQLProxySpec struct {
-	Image           string            `json:"image,omitempty"`
+	Mode  CloudSQLProxyMode `json:"mode,omitempty"`
+	Auth  CloudSQLProxyAuth `json:"auth,omitempty"`
+	Image string            `json:"image,omitempty"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
-	Replicas        int32             `json:"replicas,omitempty"`
+	// Replicas of the proxy Deployment, defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	Replicas     int32                       `json:"replicas,omitempty"`
+	Resources    corev1.ResourceRequirements `json:"resources,omitempty"`
+	NodeSelector map[string]string           `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration         `json:"tolerations,omitempty"`
+	// The affinity schema is not expanded to keep the CRD small, it is validated by the API server when the proxy Deployment is created.
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity      *corev1.Affinity                 `json:"affinity,omitempty"`
+	NetworkPolicy bool                             `json:"networkPolicy,omitempty"`
+	AllowedFrom   []networkingv1.NetworkPolicyPeer `json:"allowedFrom,omitempty"`
+	// ServiceAccounts are the Kubernetes service accounts of the app pods, as <namespace>/<name>, bound to the proxy Google service account
+	// with the sidecar mode and workloadIdentity auth. The sidecar runs as the service account of the pod, other pods are denied injection.
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+}
+
+// CloudSQLProxyMode represents the string mapping to the possible proxy.mode values.
+// +kubebuilder:validation:Enum=deployment;sidecar
+type CloudSQLProxyMode string
+
+const (
+	// CloudSQLProxyModeDeployment runs a shared proxy Deployment and Service for the instance.
+	CloudSQLProxyModeDeployment CloudSQLProxyMode = "deployment"
+	// CloudSQLProxyModeSidecar injects a proxy sidecar bound to localhost into pods labeled with an AppDB name.
+	CloudSQLProxyModeSidecar CloudSQLProxyMode = "sidecar"
+)
+
+// CloudSQLProxyAuth represents the string mapping to the possible proxy.auth values.
+// +kubebuilder:validation:Enum=serviceAccountKey;workloadIdentity
+type CloudSQLProxyAuth string
+
+const (
+	// CloudSQLProxyAuthServiceAccountKey mounts a Google service account key from a Secret into the proxy.
+	CloudSQLProxyAuthServiceAccountKey CloudSQLProxyAuth = "serviceAccountKey"
+	// CloudSQLProxyAuthWorkloadIdentity runs the proxy as a Kubernetes service account bound to the Google service account with GKE Workload Identity.
+	CloudSQLProxyAuthWorkloadIdentity CloudSQLProxyAuth = "workloadIdentity"
+)
+
+// GetAuth returns the proxy auth method, defaulting to CloudSQLProxyAuthServiceAccountKey.
+func (spec *CloudSQLProxySpec) GetAuth() CloudSQLProxyAuth {
+	if spec.Auth == "" {
+		return CloudSQLProxyAuthServiceAccountKey
+	}
+	return spec.Auth
+}
+
+// GetReplicas returns the replicas of the proxy Deployment, defaulting to 1.
+func (spec *CloudSQLProxySpec) GetReplicas() int32 {
+	if spec.Replicas == 0 {
+		return 1
+	}
+	return spec.Replicas
+}
+
+// GetMode returns the proxy mode, defaulting to CloudSQLProxyModeDeployment.
+func (spec *CloudSQLProxySpec) GetMode() CloudSQLProxyMode {
+	if spec.Mode == "" {
+		return CloudSQLProxyModeDeployment
+	}
+	return spec.Mode
 }
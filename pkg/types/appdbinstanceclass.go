@@ -0,0 +1,71 @@
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppDBInstanceClassLabel is the label added to AppDBInstances created from an AppDBInstanceClass.
+const AppDBInstanceClassLabel = "appdb.ctl.isla.solutions/instance-class"
+
+const (
+	// AppDBInstanceCreatedByClassAnnotation marks the AppDBInstances created by the operator for a class, they are marked reclaimable when the last AppDB placed on them is deleted.
+	// Instances labeled with a class by the user do not have it and are never marked reclaimable.
+	AppDBInstanceCreatedByClassAnnotation = "appdb.ctl.isla.solutions/created-by-class"
+
+	// AppDBInstanceReservationsAnnotation is the JSON map of the AppDBs placed on a class instance, as <namespace>/<name>, to their size hint.
+	// It is updated with a resourceVersion check so that concurrent placements cannot exceed the capacity of the instance.
+	AppDBInstanceReservationsAnnotation = "appdb.ctl.isla.solutions/reservations"
+
+	// AppDBInstanceReclaimableAnnotation is set to the time an instance created for a class became empty, no AppDB is placed on a reclaimable instance.
+	// The operator does not delete it, the Cloud SQL instance must be destroyed with a TerraformDestroy before the AppDBInstance is deleted.
+	// Removing the annotation returns the instance to the class.
+	AppDBInstanceReclaimableAnnotation = "appdb.ctl.isla.solutions/reclaimable"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=appdbinstanceclasses,scope=Cluster,shortName=appdbic
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.placement.namespace"
+// +kubebuilder:printcolumn:name="MaxDatabases",type="integer",JSONPath=".spec.placement.maxDatabasesPerInstance"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AppDBInstanceClass is the cluster-scoped custom resource definition structure describing a class of AppDBInstances.
+type AppDBInstanceClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              AppDBInstanceClassSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// AppDBInstanceClassList is a list of AppDBInstanceClass resources.
+type AppDBInstanceClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppDBInstanceClass `json:"items"`
+}
+
+// AppDBInstanceClassSpec is the spec of the AppDBInstances created for the class.
+type AppDBInstanceClassSpec struct {
+	Driver    AppDBDriver                 `json:"driver,omitempty"`
+	Placement AppDBInstanceClassPlacement `json:"placement,omitempty"`
+}
+
+// AppDBInstanceClassPlacement are the rules for selecting or creating an AppDBInstance of the class.
+type AppDBInstanceClassPlacement struct {
+	// Namespace where new AppDBInstances are created, defaults to the namespace of the AppDB.
+	Namespace string `json:"namespace,omitempty"`
+
+	// MaxDatabasesPerInstance is the capacity of an instance before a new instance is created, 0 is unlimited.
+	// It is copied to spec.maxDatabases of new AppDBInstances and used for existing instances of the class without spec.maxDatabases.
+	// +kubebuilder:validation:Minimum=0
+	MaxDatabasesPerInstance int32 `json:"maxDatabasesPerInstance,omitempty"`
+
+	// AllowedNamespaces is copied to the spec of new AppDBInstances.
+	// When not set and namespace is set, new instances allow all namespaces.
+	AllowedNamespaces *metav1.LabelSelector `json:"allowedNamespaces,omitempty"`
+}
@@ -1,7 +1,7 @@
 package types
 
 import (
-	tfv1 "github.com/danisla/terraform-operator/pkg/types"
+	tfv1 "github.com/danisla/appdb-operator/pkg/tfoperator"
 	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -15,6 +15,8 @@ const (
 	ProvisioningStatusComplete ProvisioningStatus = "COMPLETE"
 )
 
+// +k8s:deepcopy-gen=false
+
 // Terraform is a copy of tfv1.Terraform with the exception of the status field.
 // This is used when marshaling so that the Status field does not interfere.
 type Terraform struct {
@@ -24,6 +26,8 @@ type Terraform struct {
 	SpecFrom          tfv1.TerraformSpecFrom `json:"specFrom,omitempty"`
 }
 
+// +k8s:deepcopy-gen=false
+
 // Job is a copy of batchv1.Job with the exception of the status field.
 // This is used when marshaling so that the Status field does not interfere.
 type Job struct {
@@ -0,0 +1,5 @@
+// Package types contains the ctl.isla.solutions/v1 API types for the AppDB, AppDBInstance and AppDBInstanceClass custom resources.
+// +k8s:deepcopy-gen=package
+// +groupName=ctl.isla.solutions
+// +versionName=v1
+package types
@@ -0,0 +1,93 @@
+package v2
+
+import (
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=appdbs,shortName=appdb
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".status.appDBInstance"
+// +kubebuilder:printcolumn:name="Provisioning",type="string",JSONPath=".status.provisioning"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="DBHost",type="string",JSONPath=".status.dbHost"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AppDB is the custom resource definition structure.
+type AppDB struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              AppDBSpec   `json:"spec,omitempty"`
+	Status            AppDBStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// AppDBList is a list of AppDB resources.
+type AppDBList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppDB `json:"items"`
+}
+
+// AppDBSpec is the top level structure of the spec body
+type AppDBSpec struct {
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	AppDBInstance string `json:"appDBInstance,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	InstanceClassName string `json:"instanceClassName,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	SizeHint int32 `json:"sizeHint,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	DBName      string                        `json:"dbName,omitempty"`
+	Users       []AppDBUser                   `json:"users,omitempty"`
+	LoadURL     string                        `json:"loadURL,omitempty"`
+	Credentials *appdbv1.AppDBCredentialsSpec `json:"credentials,omitempty"`
+}
+
+// AppDBUserType represents the string mapping to the possible types of database users.
+// +kubebuilder:validation:Enum=password;iamServiceAccount
+type AppDBUserType string
+
+const (
+	// AppDBUserTypePassword is a database user with a generated password.
+	AppDBUserTypePassword AppDBUserType = "password"
+	// AppDBUserTypeIAMServiceAccount is a Cloud SQL IAM database user mapped to a Google service account.
+	AppDBUserTypeIAMServiceAccount AppDBUserType = "iamServiceAccount"
+)
+
+// AppDBUser is a database user, either with a password or authenticated with IAM.
+type AppDBUser struct {
+	// Name of the database user, required for password users. IAM user names are derived from the service account.
+	Name string        `json:"name,omitempty"`
+	Type AppDBUserType `json:"type,omitempty"`
+	// +kubebuilder:validation:Pattern=`^.+@.+\.gserviceaccount\.com$`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// GetType returns the user type, defaulting to AppDBUserTypePassword.
+func (u *AppDBUser) GetType() AppDBUserType {
+	if u.Type == "" {
+		return AppDBUserTypePassword
+	}
+	return u.Type
+}
+
+// AppDBStatus is the status structure for the custom resource
+type AppDBStatus struct {
+	Provisioning       appdbv1.ProvisioningStatus    `json:"provisioning,omitempty"`
+	DBHost             string                        `json:"dbHost,omitempty"`
+	AppDBInstance      string                        `json:"appDBInstance,omitempty"`
+	AppDBInstanceSig   string                        `json:"appDBInstanceSig,omitempty"`
+	Placement          *appdbv1.AppDBPlacementStatus `json:"placement,omitempty"`
+	DriverResources    []DriverResourceStatus        `json:"driverResources,omitempty"`
+	Vault              *appdbv1.AppDBVaultStatus     `json:"vault,omitempty"`
+	CredentialsSecrets map[string]string             `json:"credentialsSecrets,omitempty"`
+	Conditions         []appdbv1.AppDBCondition      `json:"conditions,omitempty"`
+	// ObservedGeneration is set by metacontroller after each sync.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
@@ -0,0 +1,70 @@
+package v2
+
+import (
+	appdbv1 "github.com/danisla/appdb-operator/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=appdbinstances,shortName=appdbi
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Provisioning",type="string",JSONPath=".status.provisioning"
+// +kubebuilder:printcolumn:name="DBHost",type="string",JSONPath=".status.dbHost"
+// +kubebuilder:printcolumn:name="Port",type="integer",JSONPath=".status.dbPort"
+// +kubebuilder:printcolumn:name="Databases",type="integer",JSONPath=".status.databaseCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AppDBInstance is the custom resource definition structure.
+type AppDBInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              appdbv1.AppDBInstanceSpec `json:"spec,omitempty"`
+	Status            AppDBInstanceStatus       `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// AppDBInstanceList is a list of AppDBInstance resources.
+type AppDBInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppDBInstance `json:"items"`
+}
+
+// AppDBInstanceStatus is the status structure for the custom resource
+type AppDBInstanceStatus struct {
+	Provisioning      appdbv1.ProvisioningStatus `json:"provisioning,omitempty"`
+	DBHost            string                     `json:"dbHost,omitempty"`
+	DBPort            int32                      `json:"dbPort,omitempty"`
+	DatabaseCount     int32                      `json:"databaseCount,omitempty"`
+	DatabaseLoad      int32                      `json:"databaseLoad,omitempty"`
+	RemainingCapacity *int32                     `json:"remainingCapacity,omitempty"`
+	Instance          *DatabaseInstanceStatus    `json:"instance,omitempty"`
+	Proxy             *DatabaseProxyStatus       `json:"proxy,omitempty"`
+	DriverResources   []DriverResourceStatus     `json:"driverResources,omitempty"`
+	Conditions        []appdbv1.AppDBCondition   `json:"conditions,omitempty"`
+	// ObservedGeneration is set by metacontroller after each sync.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// DatabaseInstanceStatus describes the database instance provisioned by the driver.
+type DatabaseInstanceStatus struct {
+	Name                string `json:"name,omitempty"`
+	ConnectionName      string `json:"connectionName,omitempty"`
+	ServiceAccountEmail string `json:"serviceAccountEmail,omitempty"`
+	Port                int32  `json:"port,omitempty"`
+	PrivateIPAddress    string `json:"privateIPAddress,omitempty"`
+	ServerCACert        string `json:"serverCACert,omitempty"`
+}
+
+// DatabaseProxyStatus describes the proxy resources created for the instance.
+type DatabaseProxyStatus struct {
+	Service        string                              `json:"service,omitempty"`
+	Secret         string                              `json:"secret,omitempty"`
+	ServiceAccount string                              `json:"serviceAccount,omitempty"`
+	NetworkPolicy  string                              `json:"networkPolicy,omitempty"`
+	Rollout        *appdbv1.CloudSQLProxyRolloutStatus `json:"rollout,omitempty"`
+}
@@ -0,0 +1,32 @@
+package v2
+
+// DriverResourceRole represents the string mapping to the possible roles of a resource created by a driver.
+// +kubebuilder:validation:Enum=apply;plan
+type DriverResourceRole string
+
+const (
+	// DriverResourceRoleApply is the resource that creates or updates the database.
+	DriverResourceRoleApply DriverResourceRole = "apply"
+	// DriverResourceRolePlan is the resource that previews a change to the database.
+	DriverResourceRolePlan DriverResourceRole = "plan"
+)
+
+// DriverResourceStatus is a resource the driver created to provision the database, like a TerraformApply.
+type DriverResourceStatus struct {
+	Role       DriverResourceRole `json:"role"`
+	APIVersion string             `json:"apiVersion,omitempty"`
+	Kind       string             `json:"kind,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	PodName    string             `json:"podName,omitempty"`
+	Sig        string             `json:"sig,omitempty"`
+}
+
+// GetDriverResource returns the driver resource with the given role, or nil if there is none.
+func GetDriverResource(resources []DriverResourceStatus, role DriverResourceRole) *DriverResourceStatus {
+	for i := range resources {
+		if resources[i].Role == role {
+			return &resources[i]
+		}
+	}
+	return nil
+}
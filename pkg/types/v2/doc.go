@@ -0,0 +1,6 @@
+// Package v2 contains the ctl.isla.solutions/v2 API types for the AppDB and AppDBInstance custom resources.
+// The v2 API has a driver-neutral status and structured users, objects are stored as v1 and converted by the conversion webhook.
+// +k8s:deepcopy-gen=package
+// +groupName=ctl.isla.solutions
+// +versionName=v2
+package v2
@@ -0,0 +1,143 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// DatabaseConnection is the config written to <mount>/config/<name> of the database secrets engine.
+type DatabaseConnection struct {
+	PluginName    string   `json:"plugin_name"`
+	ConnectionURL string   `json:"connection_url"`
+	Username      string   `json:"username"`
+	Password      string   `json:"password"`
+	AllowedRoles  []string `json:"allowed_roles"`
+}
+
+// DatabaseRole is the config written to <mount>/roles/<name> of the database secrets engine.
+type DatabaseRole struct {
+	DBName             string   `json:"db_name"`
+	CreationStatements []string `json:"creation_statements"`
+	DefaultTTL         string   `json:"default_ttl,omitempty"`
+	MaxTTL             string   `json:"max_ttl,omitempty"`
+}
+
+// Client is a minimal client for the Vault HTTP API.
+type Client struct {
+	config     *VaultConfig
+	httpClient *http.Client
+}
+
+// NewClient creates a new Vault client from the given config.
+func NewClient(config *VaultConfig) (*Client, error) {
+	transport := &http.Transport{}
+
+	if config.CACertFile != "" {
+		caCert, err := ioutil.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read VAULT_CACERT %s: %v", config.CACertFile, err)
+		}
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM(caCert); ok == false {
+			return nil, fmt.Errorf("No certificates found in VAULT_CACERT %s", config.CACertFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   10 * time.Second,
+		},
+	}, nil
+}
+
+// RolePath returns the path apps read to generate credentials for the given role, for example: database/creds/myrole
+func (c *Client) RolePath(mount, role string) string {
+	if mount == "" {
+		mount = c.config.DatabaseMount
+	}
+	return fmt.Sprintf("%s/creds/%s", mount, role)
+}
+
+// WriteDatabaseConnection creates or updates a database secrets engine connection.
+func (c *Client) WriteDatabaseConnection(mount, name string, conn DatabaseConnection) error {
+	if mount == "" {
+		mount = c.config.DatabaseMount
+	}
+	return c.write(fmt.Sprintf("%s/config/%s", mount, name), conn)
+}
+
+// WriteDatabaseRole creates or updates a database secrets engine role.
+func (c *Client) WriteDatabaseRole(mount, name string, role DatabaseRole) error {
+	if mount == "" {
+		mount = c.config.DatabaseMount
+	}
+	return c.write(fmt.Sprintf("%s/roles/%s", mount, name), role)
+}
+
+// DeleteDatabaseConnection deletes a database secrets engine connection, deleting a connection that does not exist succeeds.
+func (c *Client) DeleteDatabaseConnection(mount, name string) error {
+	if mount == "" {
+		mount = c.config.DatabaseMount
+	}
+	return c.do("DELETE", fmt.Sprintf("%s/config/%s", mount, name), nil)
+}
+
+// DeleteDatabaseRole deletes a database secrets engine role, deleting a role that does not exist succeeds.
+func (c *Client) DeleteDatabaseRole(mount, name string) error {
+	if mount == "" {
+		mount = c.config.DatabaseMount
+	}
+	return c.do("DELETE", fmt.Sprintf("%s/roles/%s", mount, name), nil)
+}
+
+// LookupSelf verifies that Vault is reachable and the token of the operator is valid.
+func (c *Client) LookupSelf() error {
+	return c.do("GET", "auth/token/lookup-self", nil)
+}
+
+func (c *Client) write(path string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.do("POST", path, data)
+}
+
+func (c *Client) do(method, path string, data []byte) error {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", c.config.Address, path), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", c.config.Token)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to %s Vault path %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Failed to %s Vault path %s: %s: %s", method, path, resp.Status, string(respBody))
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package vault
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeVault is a Vault HTTP server that stores the written paths in memory.
+type fakeVault struct {
+	mu    sync.Mutex
+	token string
+	data  map[string]map[string]interface{}
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *Client) {
+	fv := &fakeVault{token: "root", data: make(map[string]map[string]interface{}, 0)}
+	srv := httptest.NewServer(fv)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(&VaultConfig{Address: srv.URL, Token: "root", DatabaseMount: DEFAULT_VAULT_DATABASE_MOUNT})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return fv, c
+}
+
+func (fv *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != fv.token {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+
+	fv.mu.Lock()
+	defer fv.mu.Unlock()
+
+	switch r.Method {
+	case "POST":
+		body, _ := ioutil.ReadAll(r.Body)
+		var data map[string]interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			http.Error(w, `{"errors":["invalid JSON"]}`, http.StatusBadRequest)
+			return
+		}
+		fv.data[r.URL.Path] = data
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		delete(fv.data, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case "GET":
+		if r.URL.Path == "/v1/auth/token/lookup-self" {
+			w.Write([]byte(`{"data":{}}`))
+			return
+		}
+		http.NotFound(w, r)
+	}
+}
+
+func (fv *fakeVault) get(path string) (map[string]interface{}, bool) {
+	fv.mu.Lock()
+	defer fv.mu.Unlock()
+	data, ok := fv.data[path]
+	return data, ok
+}
+
+func TestWriteAndDeleteDatabaseRole(t *testing.T) {
+	fv, c := newFakeVault(t)
+
+	conn := DatabaseConnection{PluginName: "mysql-database-plugin", ConnectionURL: "{{username}}:{{password}}@tcp(db:3306)/", Username: "vault", Password: "secret", AllowedRoles: []string{"app"}}
+	if err := c.WriteDatabaseConnection("", "app", conn); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	role := DatabaseRole{DBName: "app", CreationStatements: []string{"CREATE USER '{{name}}';"}, DefaultTTL: "1h"}
+	if err := c.WriteDatabaseRole("", "app", role); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if data, ok := fv.get("/v1/database/config/app"); ok == false || data["plugin_name"] != "mysql-database-plugin" {
+		t.Errorf("Expected connection at database/config/app, got: %v", data)
+	}
+	if data, ok := fv.get("/v1/database/roles/app"); ok == false || data["db_name"] != "app" || data["default_ttl"] != "1h" {
+		t.Errorf("Expected role at database/roles/app, got: %v", data)
+	}
+	if got := c.RolePath("", "app"); got != "database/creds/app" {
+		t.Errorf("Expected role path database/creds/app, got: %s", got)
+	}
+
+	// Custom mounts are used as given.
+	if err := c.WriteDatabaseRole("team-db", "app", role); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := fv.get("/v1/team-db/roles/app"); ok == false {
+		t.Errorf("Expected role at team-db/roles/app")
+	}
+
+	if err := c.DeleteDatabaseRole("", "app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.DeleteDatabaseConnection("", "app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := fv.get("/v1/database/roles/app"); ok == true {
+		t.Errorf("Expected role to be deleted")
+	}
+	if _, ok := fv.get("/v1/database/config/app"); ok == true {
+		t.Errorf("Expected connection to be deleted")
+	}
+
+	// Deleting a role that does not exist succeeds.
+	if err := c.DeleteDatabaseRole("", "app"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	fv, c := newFakeVault(t)
+
+	if err := c.LookupSelf(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	fv.token = "other"
+	if err := c.LookupSelf(); err == nil {
+		t.Errorf("Expected error for a rejected token")
+	}
+	if err := c.WriteDatabaseRole("", "app", DatabaseRole{DBName: "app"}); err == nil {
+		t.Errorf("Expected error for a rejected token")
+	}
+}
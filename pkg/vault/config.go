@@ -0,0 +1,54 @@
+package vault
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	DEFAULT_VAULT_DATABASE_MOUNT = "database"
+)
+
+// VaultConfig is the config for the Vault dynamic credentials mode, the settings are read by the operator config.
+type VaultConfig struct {
+	Address       string
+	Token         string
+	TokenFile     string
+	DatabaseMount string
+	CACertFile    string
+}
+
+// Enabled returns true if a Vault address is given, Vault is only required for AppDBs with spec.credentials.mode: vaultDynamic
+func (c *VaultConfig) Enabled() bool {
+	return c.Address != ""
+}
+
+// LoadAndValidate reads the token file and validates the settings read by the operator config.
+func (c *VaultConfig) LoadAndValidate() error {
+
+	// VAULT_ADDR is required
+	if c.Address == "" {
+		return fmt.Errorf("Missing VAULT_ADDR")
+	}
+	c.Address = strings.TrimRight(c.Address, "/")
+
+	// VAULT_TOKEN or VAULT_TOKEN_FILE is required
+	if c.Token == "" && c.TokenFile != "" {
+		data, err := ioutil.ReadFile(c.TokenFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read VAULT_TOKEN_FILE %s: %v", c.TokenFile, err)
+		}
+		c.Token = strings.TrimSpace(string(data))
+	}
+	if c.Token == "" {
+		return fmt.Errorf("Missing VAULT_TOKEN or VAULT_TOKEN_FILE")
+	}
+
+	c.DatabaseMount = strings.Trim(c.DatabaseMount, "/")
+	if c.DatabaseMount == "" {
+		return fmt.Errorf("Missing VAULT_DATABASE_MOUNT")
+	}
+
+	return nil
+}
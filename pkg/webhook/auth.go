@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DEFAULT_TOKEN_REVIEW_CACHE_TTL avoids a TokenReview for every sync, metacontroller resyncs every parent every few seconds.
+	DEFAULT_TOKEN_REVIEW_CACHE_TTL = 1 * time.Minute
+)
+
+// Authenticator verifies that a request comes from metacontroller.
+type Authenticator interface {
+	Authenticate(r *http.Request) error
+}
+
+// NewAuthenticator returns the Authenticator for the auth mode of the config, clientset is used for TokenReviews.
+func NewAuthenticator(config *Config, clientset kubernetes.Interface) (Authenticator, error) {
+	switch config.AuthMode {
+	case AuthModeNone, "":
+		return noneAuthenticator{}, nil
+	case AuthModeToken:
+		return tokenAuthenticator{token: config.Token}, nil
+	case AuthModeMTLS:
+		return mtlsAuthenticator{allowedNames: config.AllowedClientNames}, nil
+	case AuthModeTokenReview:
+		return &tokenReviewAuthenticator{
+			clientset:    clientset,
+			allowedUsers: config.AllowedUsers,
+			ttl:          DEFAULT_TOKEN_REVIEW_CACHE_TTL,
+			cache:        make(map[string]time.Time, 0),
+		}, nil
+	}
+	return nil, fmt.Errorf("Unsupported auth mode: %s", config.AuthMode)
+}
+
+// errForbidden is returned for callers that authenticated but are not allowed to call the sync webhooks.
+type errForbidden struct {
+	msg string
+}
+
+func (e errForbidden) Error() string {
+	return e.msg
+}
+
+// RequireAuth rejects requests without valid credentials with 401 and callers that are not allowed with 403 before calling next.
+func RequireAuth(auth Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := auth.Authenticate(r); err != nil {
+			log.Printf("[WARN] Rejected %s %s from %s: %v", r.Method, r.URL.Path, r.RemoteAddr, err)
+			if _, ok := err.(errForbidden); ok == true {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, "Forbidden\n")
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, "Unauthorized\n")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type noneAuthenticator struct{}
+
+func (a noneAuthenticator) Authenticate(r *http.Request) error {
+	return nil
+}
+
+type tokenAuthenticator struct {
+	token string
+}
+
+func (a tokenAuthenticator) Authenticate(r *http.Request) error {
+	token, err := bearerToken(r)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+		return fmt.Errorf("Invalid token")
+	}
+	return nil
+}
+
+type mtlsAuthenticator struct {
+	allowedNames []string
+}
+
+func (a mtlsAuthenticator) Authenticate(r *http.Request) error {
+	// The certificate chain was verified against the client CA by the TLS handshake.
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return fmt.Errorf("Missing verified client certificate")
+	}
+	if len(a.allowedNames) == 0 {
+		return nil
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+	for _, name := range names {
+		for _, allowed := range a.allowedNames {
+			if name == allowed {
+				return nil
+			}
+		}
+	}
+	return errForbidden{fmt.Sprintf("Client certificate %s is not allowed", cert.Subject.CommonName)}
+}
+
+type tokenReviewAuthenticator struct {
+	clientset    kubernetes.Interface
+	allowedUsers []string
+	ttl          time.Duration
+	mu           sync.Mutex
+	cache        map[string]time.Time
+}
+
+func (a *tokenReviewAuthenticator) Authenticate(r *http.Request) error {
+	token, err := bearerToken(r)
+	if err != nil {
+		return err
+	}
+
+	// Only the hash of accepted tokens is kept.
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+
+	a.mu.Lock()
+	expires, ok := a.cache[key]
+	a.mu.Unlock()
+	if ok == true && time.Now().Before(expires) {
+		return nil
+	}
+
+	review, err := a.clientset.AuthenticationV1().TokenReviews().Create(&authv1.TokenReview{
+		Spec: authv1.TokenReviewSpec{Token: token},
+	})
+	if err != nil {
+		return fmt.Errorf("TokenReview failed: %v", err)
+	}
+	if review.Status.Authenticated == false {
+		return fmt.Errorf("Token not authenticated: %s", review.Status.Error)
+	}
+
+	allowed := false
+	for _, user := range a.allowedUsers {
+		if review.Status.User.Username == user {
+			allowed = true
+			break
+		}
+	}
+	if allowed == false {
+		return errForbidden{fmt.Sprintf("User %s is not allowed", review.Status.User.Username)}
+	}
+
+	a.mu.Lock()
+	now := time.Now()
+	for k, t := range a.cache {
+		if now.After(t) {
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = now.Add(a.ttl)
+	a.mu.Unlock()
+
+	return nil
+}
+
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") == false {
+		return "", fmt.Errorf("Missing Authorization: Bearer header")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")), nil
+}
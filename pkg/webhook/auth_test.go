@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestHandler(t *testing.T, config *Config) http.Handler {
+	clientset := fake.NewSimpleClientset()
+	// The API server authenticates the token "metacontroller-token" as the metacontroller service account and "other-token" as another user.
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.TokenReview)
+		switch review.Spec.Token {
+		case "metacontroller-token":
+			review.Status = authv1.TokenReviewStatus{Authenticated: true, User: authv1.UserInfo{Username: DEFAULT_ALLOWED_USER}}
+		case "other-token":
+			review.Status = authv1.TokenReviewStatus{Authenticated: true, User: authv1.UserInfo{Username: "system:serviceaccount:default:default"}}
+		default:
+			review.Status = authv1.TokenReviewStatus{Authenticated: false, Error: "invalid token"}
+		}
+		return true, review, nil
+	})
+
+	auth, err := NewAuthenticator(config, clientset)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return RequireAuth(auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func newTestClientCert(commonName string) *tls.ConnectionState {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+}
+
+func TestRequireAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		token      string
+		tls        *tls.ConnectionState
+		wantStatus int
+	}{
+		{"none", Config{AuthMode: AuthModeNone}, "", nil, http.StatusOK},
+		{"token missing", Config{AuthMode: AuthModeToken, Token: "secret"}, "", nil, http.StatusUnauthorized},
+		{"token invalid", Config{AuthMode: AuthModeToken, Token: "secret"}, "guess", nil, http.StatusUnauthorized},
+		{"token valid", Config{AuthMode: AuthModeToken, Token: "secret"}, "secret", nil, http.StatusOK},
+		{"mtls without certificate", Config{AuthMode: AuthModeMTLS}, "", nil, http.StatusUnauthorized},
+		{"mtls certificate not allowed", Config{AuthMode: AuthModeMTLS, AllowedClientNames: []string{"metacontroller"}}, "", newTestClientCert("other"), http.StatusForbidden},
+		{"mtls certificate allowed", Config{AuthMode: AuthModeMTLS, AllowedClientNames: []string{"metacontroller"}}, "", newTestClientCert("metacontroller"), http.StatusOK},
+		{"tokenReview missing", Config{AuthMode: AuthModeTokenReview, AllowedUsers: []string{DEFAULT_ALLOWED_USER}}, "", nil, http.StatusUnauthorized},
+		{"tokenReview not authenticated", Config{AuthMode: AuthModeTokenReview, AllowedUsers: []string{DEFAULT_ALLOWED_USER}}, "guess", nil, http.StatusUnauthorized},
+		{"tokenReview user not allowed", Config{AuthMode: AuthModeTokenReview, AllowedUsers: []string{DEFAULT_ALLOWED_USER}}, "other-token", nil, http.StatusForbidden},
+		{"tokenReview user allowed", Config{AuthMode: AuthModeTokenReview, AllowedUsers: []string{DEFAULT_ALLOWED_USER}}, "metacontroller-token", nil, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		handler := newTestHandler(t, &tc.config)
+
+		req := httptest.NewRequest("POST", "/sync/appdb", nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		req.TLS = tc.tls
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tc.wantStatus {
+			t.Errorf("%s: expected status %d, got: %d", tc.name, tc.wantStatus, rec.Code)
+		}
+	}
+}
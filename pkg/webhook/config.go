@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// AuthMode is the method used to verify that sync requests come from metacontroller.
+type AuthMode string
+
+const (
+	// AuthModeNone accepts all requests, the default for compatibility with existing installs.
+	AuthModeNone AuthMode = "none"
+	// AuthModeToken requires the shared token in the Authorization: Bearer header.
+	AuthModeToken AuthMode = "token"
+	// AuthModeMTLS requires a client certificate signed by the client CA, requires TLS.
+	AuthModeMTLS AuthMode = "mtls"
+	// AuthModeTokenReview requires a bearer token that the API server authenticates as one of the allowed users.
+	AuthModeTokenReview AuthMode = "tokenReview"
+
+	DEFAULT_ALLOWED_USER = "system:serviceaccount:metacontroller:metacontroller"
+)
+
+// Config is the TLS and authentication config of the sync webhook server, the settings are read by the operator config.
+type Config struct {
+	TLSCertFile        string
+	TLSKeyFile         string
+	AuthMode           AuthMode
+	Token              string
+	TokenFile          string
+	ClientCAFile       string
+	AllowedClientNames []string
+	AllowedUsers       []string
+}
+
+// LoadAndValidate reads the token file and validates the settings read by the operator config.
+func (c *Config) LoadAndValidate() error {
+
+	// WEBHOOK_TLS_CERT_FILE and WEBHOOK_TLS_KEY_FILE are optional, TLS is enabled when both are set.
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("WEBHOOK_TLS_CERT_FILE and WEBHOOK_TLS_KEY_FILE must be set together")
+	}
+
+	switch c.AuthMode {
+	case AuthModeNone:
+		log.Printf("[WARN] WEBHOOK_AUTH_MODE is %s, sync requests are not authenticated, limit access to the metacontroller pods with a NetworkPolicy", AuthModeNone)
+	case AuthModeToken:
+		// WEBHOOK_AUTH_TOKEN or WEBHOOK_AUTH_TOKEN_FILE is required
+		if c.Token == "" && c.TokenFile != "" {
+			data, err := ioutil.ReadFile(c.TokenFile)
+			if err != nil {
+				return fmt.Errorf("Failed to read WEBHOOK_AUTH_TOKEN_FILE %s: %v", c.TokenFile, err)
+			}
+			c.Token = strings.TrimSpace(string(data))
+		}
+		if c.Token == "" {
+			return fmt.Errorf("WEBHOOK_AUTH_MODE %s requires WEBHOOK_AUTH_TOKEN or WEBHOOK_AUTH_TOKEN_FILE", AuthModeToken)
+		}
+	case AuthModeMTLS:
+		if c.TLSCertFile == "" {
+			return fmt.Errorf("WEBHOOK_AUTH_MODE %s requires WEBHOOK_TLS_CERT_FILE and WEBHOOK_TLS_KEY_FILE", AuthModeMTLS)
+		}
+		// WEBHOOK_CLIENT_CA_FILE is required, WEBHOOK_ALLOWED_CLIENT_NAMES is optional, any certificate signed by the client CA is allowed when not set.
+		if c.ClientCAFile == "" {
+			return fmt.Errorf("WEBHOOK_AUTH_MODE %s requires WEBHOOK_CLIENT_CA_FILE", AuthModeMTLS)
+		}
+	case AuthModeTokenReview:
+		// WEBHOOK_ALLOWED_USERS is optional
+		if len(c.AllowedUsers) == 0 {
+			c.AllowedUsers = []string{DEFAULT_ALLOWED_USER}
+			log.Printf("[INFO] No WEBHOOK_ALLOWED_USERS given, using default: %s", DEFAULT_ALLOWED_USER)
+		}
+	default:
+		return fmt.Errorf("Unsupported WEBHOOK_AUTH_MODE: %s, must be one of: %s, %s, %s, %s", c.AuthMode, AuthModeNone, AuthModeToken, AuthModeMTLS, AuthModeTokenReview)
+	}
+
+	if (c.AuthMode == AuthModeToken || c.AuthMode == AuthModeTokenReview) && c.TLSEnabled() == false {
+		log.Printf("[WARN] WEBHOOK_AUTH_MODE is %s without WEBHOOK_TLS_CERT_FILE, the token is sent over plain HTTP", c.AuthMode)
+	}
+
+	return nil
+}
+
+// TLSEnabled returns true when the webhook server serves TLS.
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// TLSConfig returns the server TLS config, with mTLS the client certificates are verified against the client CA.
+// Client certificates are verified if given rather than required at the TLS layer, so that the kubelet probes of /healthz and /readyz succeed,
+// the sync handler rejects requests without a certificate.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.AuthMode == AuthModeMTLS {
+		caData, err := ioutil.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read WEBHOOK_CLIENT_CA_FILE %s: %v", c.ClientCAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if pool.AppendCertsFromPEM(caData) == false {
+			return nil, fmt.Errorf("No certificates found in WEBHOOK_CLIENT_CA_FILE %s", c.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return tlsConfig, nil
+}
@@ -27,7 +27,7 @@ spec:
         disk_size_gb: "10"
         disk_type: "PD_SSD"
       proxy:
-        image: gcr.io/cloudsql-docker/gce-proxy:1.11
+        image: gcr.io/cloudsql-docker/gce-proxy:1.16
         replicas: 1
 endef
 